@@ -0,0 +1,22 @@
+// shows text with the character substitutions made (e.g. a/ as ă in rand, * as ɨ in francis-smith) as it is typed, using /convert/substitute
+// used by the OrthoConverter and the conjugator: an input with data-preview="id" shows its preview in the element with that id,
+// and data-orthography is the name of the select or radio buttons for the orthography it is written in
+document.querySelectorAll("[data-preview]").forEach(function (input) {
+    var preview = document.getElementById(input.dataset.preview);
+    function orthography() {
+        var name = input.dataset.orthography;
+        var chosen = input.form.querySelector('select[name="' + name + '"], [name="' + name + '"]:checked');
+        return chosen ? chosen.value : "auto";
+    }
+    function update() {
+        fetch("/convert/substitute?" + new URLSearchParams({text: input.value, orthography: orthography()}))
+            .then(function (response) { return response.json(); })
+            .then(function (result) {
+                if (result.input === input.value) { // an older answer that arrives late is not shown
+                    preview.textContent = result.substituted !== result.input ? result.substituted : "";
+                }
+            });
+    }
+    input.addEventListener("input", update);
+    input.form.addEventListener("change", update);
+});
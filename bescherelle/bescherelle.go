@@ -8,13 +8,18 @@
 package bescherelle
 
 import (
+	"conjugator/converter"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
+	"html/template"
 	"net/http"
 	"os"
+	"regexp"
 	"strings"
-	"text/template"
+	"unicode"
+	"unicode/utf8"
 )
 
 type VerbType int
@@ -63,6 +68,13 @@ type Locale struct { // a struct for reading the conjugation dictionary JSON
 	SourceTitle                 string   `json:"sourcetitle"`
 	SourceField                 string   `json:"sourcefield"`
 	OrthographyRadioButtonTitle string   `json:"orthographyradiobuttontitle"`
+	SideBySideTitle             string   `json:"sidebysidetitle"`
+	SyllablesTitle              string   `json:"syllablestitle"`
+	ErrorEmptyInput             string   `json:"erroremptyinput"`
+	ErrorInputTooLong           string   `json:"errorinputtoolong"`     // with the length of the input and the most there can be
+	ErrorInvalidCharacter       string   `json:"errorinvalidcharacter"` // with the character and the name of the orthography
+	ErrorUnknownEnding          string   `json:"errorunknownending"`
+	ErrorUnknownOrthography     string   `json:"errorunknownorthography"`
 	ElietDisclaimer             string   `json:"elietdisclaimer"`
 	PejilasitDisclaimer         string   `json:"pejilasitdisclaimer"`
 	EnqasikDisclaimer           string   `json:"enqasikdisclaimer"`
@@ -74,6 +86,7 @@ type Locale struct { // a struct for reading the conjugation dictionary JSON
 	EykDisclaimer               string   `json:"eykdisclaimer"`
 	VIIDisclaimer               string   `json:"viidisclaimer"`
 	EwniaqDisclaimer            string   `json:"ewniaqdisclaimer"`
+	NeedsTranslation            []string `json:"needstranslation"` // the strings of this language that are still in english until they are translated
 }
 
 type Data struct { // for collecting the data of all tables
@@ -81,26 +94,58 @@ type Data struct { // for collecting the data of all tables
 }
 
 type Table struct { // for holding the one table
-	Title          string
-	Type           VerbType
-	RowsAndColumns [][]string
+	Title            string
+	Type             VerbType
+	RowsAndColumns   [][]string
+	Renderings       [][][]string // for the side-by-side view: every cell of RowsAndColumns in each of Orthographies
+	Orthographies    []string
+	OrthographyNames []string // the names of Orthographies as shown on the page
+}
+
+type OrthographyChoice struct { // one orthography the user can choose on the page
+	Value      string // the name used by the converter package, e.g. "francissmith"
+	Name       string // the name shown on the page, e.g. "Francis-Smith"
+	Selected   bool   // if this is the orthography the user is writing in
+	SideBySide bool   // if this orthography is shown in the side-by-side view
+}
+
+type JSONOutput struct { // the conjugation as json, for other programs
+	Input       string      `json:"input"`
+	Orthography string      `json:"orthography"`
+	Conjugation string      `json:"conjugation"`
+	Model       string      `json:"model"`
+	Disclaimer  string      `json:"disclaimer,omitempty"` // as plain text, without the html of the page
+	Error       string      `json:"error,omitempty"`      // why the verb could not be conjugated, in the language of the page, as plain text
+	Code        string      `json:"code,omitempty"`       // the kind of error, e.g. "unknown_ending" (see converter.ErrorCode)
+	Tables      []JSONTable `json:"tables"`
+}
+
+type JSONTable struct { // one table of the json output
+	Title  string    `json:"title"`
+	Header []string  `json:"header,omitempty"` // the objects, for transitive tables
+	Rows   []JSONRow `json:"rows"`
+}
+
+type JSONRow struct { // one person of a json table
+	Person string              `json:"person"`
+	Forms  []map[string]string `json:"forms"` // one form for each object (or just one for intransitives), keyed by orthography
 }
 
 type DisclaimerType struct { // this holds whether there is a disclaimer (Defined, bool), and what it is (DisclaimerText)
 	Defined        bool
-	DisclaimerText string
+	DisclaimerText template.HTML
 }
 
 type MainPage struct { // this is what will be sent to the page
 	Title                       string
-	OrthographyTooltip          string
-	EntryPrompt                 string
+	OrthographyTooltip          template.HTML // html from localization.json
+	EntryPrompt                 template.HTML // html from localization.json
 	SummaryDetails              string
 	LanguageFieldLabel          string
 	English                     string
 	Mikmaw                      string
 	French                      string
-	ConjugateButton             string
+	ConjugateButton             template.HTML // html from localization.json
 	OutputConjugationTitle      string
 	OutputConjugation           string
 	OutputModelTitle            string
@@ -109,112 +154,161 @@ type MainPage struct { // this is what will be sent to the page
 	InputString                 string
 	InfoTitle                   string
 	HelpTitle                   string
-	HelpField                   string
+	HelpField                   template.HTML // html from localization.json
 	SourceTitle                 string
-	SourceField                 string
+	SourceField                 template.HTML // html from localization.json
 	LinksTitle                  string
 	ContactMe                   string
 	HomePage                    string
 	OrthographyRadioButtonTitle string
+	Orthography                 string // the orthography chosen by the user, so the page can keep it selected
+	DetectedOrthography         string // the name of the orthography, if it was detected automatically
+	SideBySide                  []string
+	SideBySideTitle             string
+	Syllables                   bool // if every form is split into syllables with the stress marked
+	SyllablesTitle              string
+	OrthographyChoices          []OrthographyChoice
 	Disclaimer                  DisclaimerType
+	Error                       template.HTML // why the verb could not be conjugated, if it could not, with the input in it escaped
+	ErrorCode                   string
 	TableData                   Data
 }
 
+// the error returned for a verb whose ending is not one of the verb patterns the conjugator knows
+// input that is empty, too long, or has characters that are not letters returns the errors of converter.ValidateWord
+var ErrUnknownEnding = errors.New("verb unrecognized")
+
+// the model verbs of every conjugation and variant (see localizeOutput)
+var ModelVerbs = []string{
+	"ajipuna't", "amalkat", "e'natl", "eliet", "enqa'sik", "ewi'kiket", "ewi'kɨk", "ewniaq", "eyk", "kesalatl", "kesatk",
+	"ketkwi'k", "ketuk", "kisituatl", "maqatkwik", "mena'toq", "nemiatl", "nenk", "nestɨk", "pejila'sit", "pekisink",
+	"pesa'tl", "pesaq", "pewa'q", "te'sipunqek", "telamu'k", "telte'k", "teluet", "teluisit", "teweket", "wekayk", "wele'k",
+}
+
 var ConjugationDictionary = make(map[string][]string) // define a global conjugation dictionary to hold the readout of the .json file
 var LocalizationDictionary = make(map[string]Locale)  // define a global localization lookup for all strings
 
+// registers the conjugator pages with net/http, after reading the dictionaries
 func ConjugatorInit() error {
+	loadErr := LoadDictionaries()
+	if loadErr != nil {
+		return loadErr
+	}
+	fmt.Println("Successfully read conjdict.json.")
+	fmt.Println("Successfully read localization.json.")
+
+	http.HandleFunc("/eng", engIndexHandler) // create a webpage for english
+	http.HandleFunc("/mkw", mkwIndexHandler) // create a webpage for mi'kmaw
+	http.HandleFunc("/fre", freIndexHandler) // create a webpage for mi'kmaw
+
+	return nil
+}
+
+// reads the conjugation dictionary and the localization dictionary, from the root of the repository
+// this is all that is needed to conjugate verbs without the pages, e.g. in CrossCheck, so it prints nothing and leaves the errors to the caller
+func LoadDictionaries() error {
 	conjugationDictionaryFile, ErrFileOpen := os.Open("bescherelle/conjdict.json") // open the json file
 	if ErrFileOpen != nil {                                                        // if there is an error
-		fmt.Println(ErrFileOpen)
 		return ErrFileOpen
 	}
-	fmt.Println("Successfully opened conjdict.json.")
 
 	defer conjugationDictionaryFile.Close() // defer closing until we are done using it
 
 	conjugationDictionaryBytes, ErrFileRead := os.ReadFile("bescherelle/conjdict.json") // read the file into a byte array
 	if ErrFileRead != nil {                                                             // if there is an error
-		fmt.Println(ErrFileRead)
 		return ErrFileRead
 	}
-	fmt.Println("Successfully read conjdict.json.")
 
 	json.Unmarshal([]byte(conjugationDictionaryBytes), &ConjugationDictionary) // use the json package to unmarshal the byte array into the conjugation dictionary
 
 	localizationFile, ErrFileOpen := os.Open("bescherelle/localization.json") // open the json file
 	if ErrFileOpen != nil {                                                   // if there is an error
-		fmt.Println(ErrFileOpen)
 		return ErrFileOpen
 	}
-	fmt.Println("Successfully opened localization.json.")
 
 	defer localizationFile.Close() // defer closing until we are done using it
 
 	localizationBytes, ErrFileRead := os.ReadFile("bescherelle/localization.json") // read the file into a byte array
 	if ErrFileRead != nil {                                                        // if there is an error
-		fmt.Println(ErrFileRead)
 		return ErrFileRead
 	}
-	fmt.Println("Successfully read localization.json.")
 
 	json.Unmarshal([]byte(localizationBytes), &LocalizationDictionary) // use the json package to unmarshal the byte array into the conjugation dictionary
 
-	http.HandleFunc("/eng", engIndexHandler) // create a webpage for english
-	http.HandleFunc("/mkw", mkwIndexHandler) // create a webpage for mi'kmaw
-	http.HandleFunc("/fre", freIndexHandler) // create a webpage for mi'kmaw
-
 	return nil
 }
 
 // this is the function that handles displaying the webpage for english
 func engIndexHandler(writer http.ResponseWriter, reader *http.Request) {
-	var WriteData Data                    // the tables to be sent to the template
-	var page MainPage                     // all the fields that get passed to the template (incl. WriteData)
-	var languageChoice string = "ENGL"    // string to get localization in english
-	if reader.Method == http.MethodPost { // if the "submit/conjugate" button is pressed
-		var InputStr string
-		InputStr = reader.FormValue("verbinput")                        // get the input string
-		var ConjugationArray [][]string                                 // load a conjugation array
-		var InputVerb Verb                                              // load an InputVerb Verb type
-		orthographyChoice := reader.FormValue("orthographyradiobutton") // a string value correstponding to the orthography chosen by the user
-		// 0 = francis smith
-		// 1 = listuguj
-		// 2 = metallic
-		if InputStr != "" { // if the input is not empty
-			if orthographyChoice == "1" {
-				InputStr = convertListugujtoFrancisSmith(InputStr) // if the user has chosen listuguj orthography, convert it to francis smith to run the program
-			} else if orthographyChoice == "2" {
-				InputStr = convertMetallictoFrancisSmith(InputStr) // if the user has chosen metallic orthography, convert it to francis smith to run the program
-			}
-			ConjugationArray, InputVerb = readoutVerb(InputStr) // fill the conjugation array and input verb structs
-			if orthographyChoice == "1" {
-				ConjugationArray = convertFrancisSmithtoListuguj(ConjugationArray) // if the user has chosen listuguj orthography, convert all tables to listuguj
-			} else if orthographyChoice == "2" {
-				ConjugationArray = convertFrancisSmithtoMetallic(ConjugationArray) // if the user has chosen metallic orthography, convert all tables to metallic
-			}
-			// make the tables differently for each verb type (VII, VAI, VTI, VTA) — point to a different function each time to do this
-			if InputVerb.Type == VAI {
-				WriteData = makeTablesVAI(ConjugationArray, languageChoice) // make the tables with this array based on localization language
-			} else if InputVerb.Type == VTI {
-				WriteData = makeTablesVTI(ConjugationArray, languageChoice) // make the tables with this array based on localization language
-			} else if InputVerb.Type == VTA {
-				WriteData = makeTablesVTA(ConjugationArray, languageChoice) // make the tables with this array based on localization language
-			} else if InputVerb.Type == VII {
-				WriteData = makeTablesVII(ConjugationArray, languageChoice) // make the tables with this array based on localization language
-			}
+	conjugatorIndexHandler(writer, reader, "ENGL") // string to get localization in english
+}
+
+// this is the same as engIndexHandler but with languageChoice MKMW (i.e. in mi'kmaw)
+func mkwIndexHandler(writer http.ResponseWriter, reader *http.Request) {
+	conjugatorIndexHandler(writer, reader, "MKMW") // string to get localization in mi'kmaw
+}
+
+// this is the same as engIndexHandler but with languageChoice FREN (i.e. in french)
+func freIndexHandler(writer http.ResponseWriter, reader *http.Request) {
+	conjugatorIndexHandler(writer, reader, "FREN") // string to get localization in french
+}
+
+// this handles displaying the conjugator page in any language
+func conjugatorIndexHandler(writer http.ResponseWriter, reader *http.Request, languageChoice string) {
+	var WriteData Data                                                           // the tables to be sent to the template
+	var page MainPage                                                            // all the fields that get passed to the template (incl. WriteData)
+	var InputVerb Verb                                                           // load an InputVerb Verb type
+	var conjugateErr error                                                       // why the verb cannot be conjugated, if it cannot
+	InputStr := "teluisit"                                                       // on first load of the page, "teluisit" is the default (Pacifique's first conjugation model)
+	orthographyChoice := "francissmith"                                          // a string value corresponding to the orthography chosen by the user (any of converter.Orthographies)
+	if reader.Method == http.MethodPost || reader.FormValue("verbinput") != "" { // if the "submit/conjugate" button is pressed, or the verb is given in the address
+		InputStr = strings.TrimSpace(reader.FormValue("verbinput")) // get the input string
+		if reader.FormValue("orthographyradiobutton") != "" {
+			orthographyChoice = reader.FormValue("orthographyradiobutton")
 		}
-		page.OutputConjugation, page.OutputModel, page.Disclaimer = localizeOutput(languageChoice, InputVerb) // localize the output (get the conjugation, model, and disclaimers)
-		page.InputString = InputStr                                                                           // the input string to be sent to the page (to be displayed as "you entered:")
-	} else { // if the button was not pressed (i.e. on first load of the page without cache)
-		ConjugationArray, InputVerb := readoutVerb("teluisit")                                                // load the conjugation array and InputVerb for "teluisit" as a default (Pacifique's first conjugation model)
-		WriteData = makeTablesVAI(ConjugationArray, languageChoice)                                           // make the tables with this array based on localization language
-		page.OutputConjugation, page.OutputModel, page.Disclaimer = localizeOutput(languageChoice, InputVerb) // localize the output (get the conjugation, model, and disclaimers)
-		page.InputString = "teluisit"                                                                         // the input string is "teluisit"
 	}
+	if orthographyChoice == "auto" && InputStr != "" { // if the user does not know the orthography, guess it and select it on the page
+		orthographyChoice = converter.DetectOrthography(InputStr).Orthography
+		page.DetectedOrthography = converter.OrthographyNames[orthographyChoice]
+	}
+	sideBySide := readSideBySide(reader)                               // the orthographies to show in every cell, if the user has asked for more than one
+	syllabified := reader.FormValue("syllables") != ""                 // if the user wants to see the syllables and stress of every form
+	var ConjugationArray [][]string                                    // load a conjugation array
+	conjugateErr = converter.ValidateWord(InputStr, orthographyChoice) // empty input, or not a single word of the orthography
+	if conjugateErr == nil {
+		// if the user has chosen another orthography, convert the input to francis smith to run the program
+		FrancisSmithStr := convertForm(strings.ToLower(InputStr), orthographyChoice, "francissmith", false)
+		ConjugationArray, InputVerb, conjugateErr = readoutVerb(FrancisSmithStr) // fill the conjugation array and input verb structs
+		if conjugateErr != nil {
+			fmt.Println(conjugateErr)
+		}
+	}
+	if conjugateErr != nil { // the tables are left empty, and the page says why
+		page.Error, page.ErrorCode = localizeError(languageChoice, conjugateErr)
+	} else if len(sideBySide) > 0 {
+		WriteData = makeSideBySideTables(ConjugationArray, InputVerb, languageChoice, sideBySide, syllabified) // make the tables once for each orthography and put them together
+	} else {
+		ConjugationArray = convertConjugationArray(ConjugationArray, orthographyChoice, syllabified) // convert all tables to the orthography the user has chosen
+		WriteData = makeTables(ConjugationArray, InputVerb, languageChoice)                          // make the tables differently for each verb type (VII, VAI, VTI, VTA)
+	}
+	page.OutputConjugation, page.OutputModel, page.Disclaimer = localizeOutput(languageChoice, InputVerb) // localize the output (get the conjugation, model, and disclaimers)
+	page.InputString = InputStr                                                                           // the input string to be sent to the page (to be displayed as "you entered:")
+	page.Orthography = orthographyChoice
+	page.SideBySide = sideBySide
+	page.Syllables = syllabified
+	page.OrthographyChoices = makeOrthographyChoices(orthographyChoice, sideBySide)
 	page = localize(page, languageChoice) // localize everything else in the page (title, buttons, etc.)
 	page.TableData = WriteData            // the tabledata is writedata (load the tables into the struct to be sent to the template)
 
+	if reader.FormValue("format") == "json" { // the same page as json, for other programs
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		encodeErr := json.NewEncoder(writer).Encode(makeJSONOutput(page))
+		if encodeErr != nil {
+			fmt.Println(encodeErr)
+		}
+		return
+	}
+
 	template, templateBuildErr := template.ParseFiles("bescherelle/conjugatortemplate.html.temp") // parse conjugatortemplate.html.temp
 	if templateBuildErr != nil {                                                                  // if an error is thrown
 		fmt.Println(templateBuildErr)
@@ -222,114 +316,133 @@ func engIndexHandler(writer http.ResponseWriter, reader *http.Request) {
 	template.Execute(writer, page) // execute the template
 }
 
-// this is the same as engIndexHandler but with languageChoice MKMW (i.e. in mi'kmaw)
-func mkwIndexHandler(writer http.ResponseWriter, reader *http.Request) {
-	var WriteData Data                    // the tables to be sent to the template
-	var page MainPage                     // all the fields that get passed to the template (incl. WriteData)
-	var languageChoice string = "MKMW"    // string to get localization in english
-	if reader.Method == http.MethodPost { // if the "submit/conjugate" button is pressed
-		var InputStr string
-		InputStr = reader.FormValue("verbinput")                        // get the input string
-		var ConjugationArray [][]string                                 // load a conjugation array
-		var InputVerb Verb                                              // load an InputVerb Verb type
-		orthographyChoice := reader.FormValue("orthographyradiobutton") // a string value correstponding to the orthography chosen by the user
-		// 0 = francis smith
-		// 1 = listuguj
-		// 2 = metallic
-		if InputStr != "" { // if the input is not empty
-			if orthographyChoice == "1" {
-				InputStr = convertListugujtoFrancisSmith(InputStr) // if the user has chosen listuguj orthography, convert it to francis smith to run the program
-			} else if orthographyChoice == "2" {
-				InputStr = convertMetallictoFrancisSmith(InputStr) // if the user has chosen metallic orthography, convert it to francis smith to run the program
-			}
-			ConjugationArray, InputVerb = readoutVerb(InputStr) // fill the conjugation array and input verb structs
-			if orthographyChoice == "1" {
-				ConjugationArray = convertFrancisSmithtoListuguj(ConjugationArray) // if the user has chosen listuguj orthography, convert all tables to listuguj
-			} else if orthographyChoice == "2" {
-				ConjugationArray = convertFrancisSmithtoMetallic(ConjugationArray) // if the user has chosen metallic orthography, convert all tables to metallic
-			}
-			// make the tables differently for each verb type (VII, VAI, VTI, VTA) — point to a different function each time to do this
-			if InputVerb.Type == VAI {
-				WriteData = makeTablesVAI(ConjugationArray, languageChoice) // make the tables with this array based on localization language
-			} else if InputVerb.Type == VTI {
-				WriteData = makeTablesVTI(ConjugationArray, languageChoice) // make the tables with this array based on localization language
-			} else if InputVerb.Type == VTA {
-				WriteData = makeTablesVTA(ConjugationArray, languageChoice) // make the tables with this array based on localization language
-			} else if InputVerb.Type == VII {
-				WriteData = makeTablesVII(ConjugationArray, languageChoice) // make the tables with this array based on localization language
+// reads the orthographies the user wants to see side by side from the checkboxes (or a comma-separated list in the address)
+func readSideBySide(reader *http.Request) []string {
+	var sideBySide []string
+	reader.ParseForm()
+	for _, value := range reader.Form["sidebyside"] {
+		for _, orthographyChoice := range strings.Split(value, ",") {
+			if _, found := converter.OrthographyNames[orthographyChoice]; found {
+				sideBySide = append(sideBySide, orthographyChoice)
 			}
 		}
-		page.OutputConjugation, page.OutputModel, page.Disclaimer = localizeOutput(languageChoice, InputVerb) // localize the output (get the conjugation, model, and disclaimers)
-		page.InputString = InputStr                                                                           // the input string to be sent to the page (to be displayed as "you entered:")
-	} else { // if the button was not pressed (i.e. on first load of the page without cache)
-		ConjugationArray, InputVerb := readoutVerb("teluisit")                                                // load the conjugation array and InputVerb for "teluisit" as a default (Pacifique's first conjugation model)
-		WriteData = makeTablesVAI(ConjugationArray, languageChoice)                                           // make the tables with this array based on localization language
-		page.OutputConjugation, page.OutputModel, page.Disclaimer = localizeOutput(languageChoice, InputVerb) // localize the output (get the conjugation, model, and disclaimers)
-		page.InputString = "teluisit"                                                                         // the input string is "teluisit"
 	}
-	page = localize(page, languageChoice) // localize everything else in the page (title, buttons, etc.)
-	page.TableData = WriteData            // the tabledata is writedata (load the tables into the struct to be sent to the template)
+	return sideBySide
+}
 
-	template, templateBuildErr := template.ParseFiles("bescherelle/conjugatortemplate.html.temp") // parse conjugatortemplate.html.temp
-	if templateBuildErr != nil {                                                                  // if an error is thrown
-		fmt.Println(templateBuildErr)
+// the choices of orthography shown on the page, in the order the converter lists them
+func makeOrthographyChoices(orthographyChoice string, sideBySide []string) []OrthographyChoice {
+	var OrthographyChoices []OrthographyChoice
+	for _, orthography := range converter.Orthographies {
+		var choice OrthographyChoice
+		choice.Value = orthography
+		choice.Name = converter.OrthographyNames[orthography]
+		choice.Selected = orthography == orthographyChoice
+		for _, sideBySideOrthography := range sideBySide {
+			if orthography == sideBySideOrthography {
+				choice.SideBySide = true
+			}
+		}
+		OrthographyChoices = append(OrthographyChoices, choice)
 	}
-	template.Execute(writer, page) // execute the template
+	return OrthographyChoices
 }
 
-// this is the same as engIndexHandler but with languageChoice FREN (i.e. in french)
-func freIndexHandler(writer http.ResponseWriter, reader *http.Request) {
-	var WriteData Data                    // the tables to be sent to the template
-	var page MainPage                     // all the fields that get passed to the template (incl. WriteData)
-	var languageChoice string = "FREN"    // string to get localization in english
-	if reader.Method == http.MethodPost { // if the "submit/conjugate" button is pressed
-		var InputStr string
-		InputStr = reader.FormValue("verbinput")                        // get the input string
-		var ConjugationArray [][]string                                 // load a conjugation array
-		var InputVerb Verb                                              // load an InputVerb Verb type
-		orthographyChoice := reader.FormValue("orthographyradiobutton") // a string value correstponding to the orthography chosen by the user
-		// 0 = francis smith
-		// 1 = listuguj
-		// 2 = metallic
-		if InputStr != "" { // if the input is not empty
-			if orthographyChoice == "1" {
-				InputStr = convertListugujtoFrancisSmith(InputStr) // if the user has chosen listuguj orthography, convert it to francis smith to run the program
-			} else if orthographyChoice == "2" {
-				InputStr = convertMetallictoFrancisSmith(InputStr) // if the user has chosen metallic orthography, convert it to francis smith to run the program
+// makes the tables once for every orthography in sideBySide and puts them together, so every cell holds its form in each orthography
+func makeSideBySideTables(ConjugationArray [][]string, InputVerb Verb, languageChoice string, sideBySide []string, syllabified bool) Data {
+	var tablesByOrthography []Data
+	for _, orthographyChoice := range sideBySide {
+		var copiedArray [][]string // convertConjugationArray changes the array it is given, so each orthography needs its own copy
+		for _, slice := range ConjugationArray {
+			copiedArray = append(copiedArray, append([]string(nil), slice...))
+		}
+		copiedArray = convertConjugationArray(copiedArray, orthographyChoice, syllabified)
+		tablesByOrthography = append(tablesByOrthography, makeTables(copiedArray, InputVerb, languageChoice))
+	}
+
+	OutputData := tablesByOrthography[0] // the first orthography chosen is the one used for headers and the plain RowsAndColumns
+	for tableIndex := range OutputData.Tables {
+		var renderings [][][]string
+		for rowIndex, row := range OutputData.Tables[tableIndex].RowsAndColumns {
+			var renderedRow [][]string
+			for cellIndex := range row {
+				var renderedCell []string
+				for _, orthographyTables := range tablesByOrthography {
+					renderedCell = append(renderedCell, orthographyTables.Tables[tableIndex].RowsAndColumns[rowIndex][cellIndex])
+				}
+				renderedRow = append(renderedRow, renderedCell)
 			}
-			ConjugationArray, InputVerb = readoutVerb(InputStr) // fill the conjugation array and input verb structs
-			if orthographyChoice == "1" {
-				ConjugationArray = convertFrancisSmithtoListuguj(ConjugationArray) // if the user has chosen listuguj orthography, convert all tables to listuguj
-			} else if orthographyChoice == "2" {
-				ConjugationArray = convertFrancisSmithtoMetallic(ConjugationArray) // if the user has chosen metallic orthography, convert all tables to metallic
+			renderings = append(renderings, renderedRow)
+		}
+		OutputData.Tables[tableIndex].Renderings = renderings
+		OutputData.Tables[tableIndex].Orthographies = sideBySide
+		for _, orthographyChoice := range sideBySide {
+			OutputData.Tables[tableIndex].OrthographyNames = append(OutputData.Tables[tableIndex].OrthographyNames, converter.OrthographyNames[orthographyChoice])
+		}
+	}
+	return OutputData
+}
+
+// turns the page into the json output: the verb, its classification, and every table with the forms keyed by orthography
+func makeJSONOutput(page MainPage) JSONOutput {
+	var Output JSONOutput
+	Output.Input = page.InputString
+	Output.Orthography = page.Orthography
+	Output.Conjugation = page.OutputConjugation
+	Output.Model = page.OutputModel
+	if page.Disclaimer.Defined {
+		Output.Disclaimer = plainText(page.Disclaimer.DisclaimerText)
+	}
+	Output.Error, Output.Code = plainText(page.Error), page.ErrorCode
+	Output.Tables = []JSONTable{} // no tables, if the verb could not be conjugated
+	for _, table := range page.TableData.Tables {
+		var OutputTable JSONTable
+		OutputTable.Title = table.Title
+		orthographies := table.Orthographies
+		if len(orthographies) == 0 { // if the table is in one orthography only
+			orthographies = []string{page.Orthography}
+		}
+		for rowIndex, row := range table.RowsAndColumns {
+			if rowIndex == 0 && (table.Type == VTI || table.Type == VTA) { // the first row of transitive tables is the object header
+				OutputTable.Header = row[1:]
+				continue
 			}
-			// make the tables differently for each verb type (VII, VAI, VTI, VTA) — point to a different function each time to do this
-			if InputVerb.Type == VAI {
-				WriteData = makeTablesVAI(ConjugationArray, languageChoice) // make the tables with this array based on localization language
-			} else if InputVerb.Type == VTI {
-				WriteData = makeTablesVTI(ConjugationArray, languageChoice) // make the tables with this array based on localization language
-			} else if InputVerb.Type == VTA {
-				WriteData = makeTablesVTA(ConjugationArray, languageChoice) // make the tables with this array based on localization language
-			} else if InputVerb.Type == VII {
-				WriteData = makeTablesVII(ConjugationArray, languageChoice) // make the tables with this array based on localization language
+			var OutputRow JSONRow
+			OutputRow.Person = row[0]
+			for cellIndex := 1; cellIndex < len(row); cellIndex++ {
+				forms := make(map[string]string)
+				for orthographyIndex, orthography := range orthographies {
+					if len(table.Renderings) > 0 {
+						forms[orthography] = table.Renderings[rowIndex][cellIndex][orthographyIndex]
+					} else {
+						forms[orthography] = row[cellIndex]
+					}
+				}
+				OutputRow.Forms = append(OutputRow.Forms, forms)
 			}
+			OutputTable.Rows = append(OutputTable.Rows, OutputRow)
 		}
-		page.OutputConjugation, page.OutputModel, page.Disclaimer = localizeOutput(languageChoice, InputVerb) // localize the output (get the conjugation, model, and disclaimers)
-		page.InputString = InputStr                                                                           // the input string to be sent to the page (to be displayed as "you entered:")
-	} else { // if the button was not pressed (i.e. on first load of the page without cache)
-		ConjugationArray, InputVerb := readoutVerb("teluisit")                                                // load the conjugation array and InputVerb for "teluisit" as a default (Pacifique's first conjugation model)
-		WriteData = makeTablesVAI(ConjugationArray, languageChoice)                                           // make the tables with this array based on localization language
-		page.OutputConjugation, page.OutputModel, page.Disclaimer = localizeOutput(languageChoice, InputVerb) // localize the output (get the conjugation, model, and disclaimers)
-		page.InputString = "teluisit"                                                                         // the input string is "teluisit"
+		Output.Tables = append(Output.Tables, OutputTable)
 	}
-	page = localize(page, languageChoice) // localize everything else in the page (title, buttons, etc.)
-	page.TableData = WriteData            // the tabledata is writedata (load the tables into the struct to be sent to the template)
+	return Output
+}
 
-	template, templateBuildErr := template.ParseFiles("bescherelle/conjugatortemplate.html.temp") // parse conjugatortemplate.html.temp
-	if templateBuildErr != nil {                                                                  // if an error is thrown
-		fmt.Println(templateBuildErr)
+// makes the tables for the verb type of the input verb
+func makeTables(ConjugationArray [][]string, InputVerb Verb, languageChoice string) Data {
+	var WriteData Data
+	if len(ConjugationArray) == 0 { // if the verb was not recognized there is nothing to make tables from
+		return WriteData
+	}
+	if InputVerb.Type == VAI {
+		WriteData = makeTablesVAI(ConjugationArray, languageChoice) // make the tables with this array based on localization language
+	} else if InputVerb.Type == VTI {
+		WriteData = makeTablesVTI(ConjugationArray, languageChoice) // make the tables with this array based on localization language
+	} else if InputVerb.Type == VTA {
+		WriteData = makeTablesVTA(ConjugationArray, languageChoice) // make the tables with this array based on localization language
+	} else if InputVerb.Type == VII {
+		WriteData = makeTablesVII(ConjugationArray, languageChoice) // make the tables with this array based on localization language
 	}
-	template.Execute(writer, page) // execute the template
+	return WriteData
 }
 
 func IsConsonant(category string) bool { // returns true if the passed slice is in this list
@@ -382,156 +495,40 @@ func IsSonorant(category string) bool { // returns true if the passed slice is i
 	return false
 }
 
-// converts anything written in listuguj orthography into francis-smith
-func convertListugujtoFrancisSmith(InputStr string) string {
-	OutputStr := strings.ToLower(InputStr)
-	// everything below is just replacing character combinations with others
-	// the output string is in francis-smith
-	OutputStr = strings.Replace(OutputStr, "g", "k", -1)
-	OutputStr = strings.Replace(OutputStr, "ai", "ay", -1)
-	OutputStr = strings.Replace(OutputStr, "a'i", "a'y", -1)
-	OutputStr = strings.Replace(OutputStr, "ei", "ey", -1)
-	OutputStr = strings.Replace(OutputStr, "e'i", "e'y", -1)
-	// replace all apostrophes after consonants as schwas, but use the escape character "*"
-	OutputStr = strings.Replace(OutputStr, "j'", "j*", -1)
-	OutputStr = strings.Replace(OutputStr, "k'", "k*", -1)
-	OutputStr = strings.Replace(OutputStr, "m'", "m*", -1)
-	OutputStr = strings.Replace(OutputStr, "n'", "n*", -1)
-	OutputStr = strings.Replace(OutputStr, "p'", "p*", -1)
-	OutputStr = strings.Replace(OutputStr, "q'", "q*", -1)
-	OutputStr = strings.Replace(OutputStr, "s'", "s*", -1)
-	OutputStr = strings.Replace(OutputStr, "t'", "t*", -1)
-	return OutputStr
-}
-
-// converts anything written in metallic orthography into francis-smith
-func convertMetallictoFrancisSmith(InputStr string) string {
-	OutputStr := strings.ToLower(InputStr)
-	// everything below is just replacing character combinations with others
-	// the output string is in francis-smith
-	OutputStr = strings.Replace(OutputStr, "b", "p", -1)
-	OutputStr = strings.Replace(OutputStr, "d", "t", -1)
-	OutputStr = strings.Replace(OutputStr, "ch", "j", -1)
-	OutputStr = strings.Replace(OutputStr, "g", "k", -1)
-	OutputStr = strings.Replace(OutputStr, "ê", "*", -1) // replace schwa with star
-	OutputStr = strings.Replace(OutputStr, "à", "a'", -1)
-	OutputStr = strings.Replace(OutputStr, "è", "e'", -1)
-	OutputStr = strings.Replace(OutputStr, "ì", "i'", -1)
-	OutputStr = strings.Replace(OutputStr, "ò", "o'", -1)
-	OutputStr = strings.Replace(OutputStr, "ù", "u'", -1)
-	return OutputStr
-}
-
-// converts anything written in francis-smith into listuguj
-func convertFrancisSmithtoListuguj(InputArray [][]string) [][]string {
+// converts every form in the conjugation array from francis-smith into the chosen orthography, split into syllables if syllabified is true
+func convertConjugationArray(InputArray [][]string, orthographyChoice string, syllabified bool) [][]string {
 	for sliceIndex := range InputArray {
 		for stringIndex, str := range InputArray[sliceIndex] {
-			outputStr := str
-			outputStr = strings.Replace(outputStr, "k", "g", -1)
-			outputStr = strings.Replace(outputStr, "ɨ", "'", -1)
-			outputStr = strings.Replace(outputStr, "y", "i", -1)
-			// converting "y" to "i" in strings of "yi" will lead to "ii"
-			outputStr = strings.Replace(outputStr, "ii", "i", -1)
-			InputArray[sliceIndex][stringIndex] = outputStr
+			InputArray[sliceIndex][stringIndex] = convertForm(str, "francissmith", orthographyChoice, syllabified)
 		}
 	}
 	return InputArray
 }
 
-// converts anything written in francis-smith into metallic
-func convertFrancisSmithtoMetallic(InputArray [][]string) [][]string {
-	for sliceIndex := range InputArray {
-		for stringIndex, str := range InputArray[sliceIndex] {
-			outputStr := str
-			outputStr = strings.Replace(outputStr, "kw", "$", -1)
-			outputStr = strings.Replace(outputStr, "j", "c", -1)
-			outputStr = strings.Replace(outputStr, "cc", "tc", -1)
-			outputStr = strings.Replace(outputStr, "ɨ", "ê", -1)
-			if len(outputStr) > 1 {
-				for charIndex, character := range outputStr {
-					if charIndex == 0 {
-						if !IsConsonant(string(outputStr[charIndex+1])) || IsSonorant(string(outputStr[charIndex+1])) {
-							if string(character) == "t" {
-								outputStr = fmt.Sprintf("d%s", string(outputStr[charIndex+1:]))
-							} else if string(character) == "p" {
-								outputStr = fmt.Sprintf("b%s", string(outputStr[charIndex+1:]))
-							} else if string(character) == "k" {
-								outputStr = fmt.Sprintf("g%s", string(outputStr[charIndex+1:]))
-							} else if string(character) == "$" {
-								outputStr = fmt.Sprintf("#%s", string(outputStr[charIndex+1:]))
-							} else if string(character) == "c" {
-								outputStr = fmt.Sprintf("j%s", string(outputStr[charIndex+1:]))
-							}
-						}
-					} else if charIndex != len(outputStr)-1 {
-						if string(outputStr[charIndex+1]) != "," {
-							if (!IsConsonant(string(outputStr[charIndex+1])) || IsSonorant(string(outputStr[charIndex+1]))) && !IsConsonant(string(outputStr[charIndex-1])) {
-								if string(character) == "t" {
-									outputStr = fmt.Sprintf("%sd%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
-								} else if string(character) == "p" {
-									outputStr = fmt.Sprintf("%sb%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
-								} else if string(character) == "k" {
-									outputStr = fmt.Sprintf("%sg%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
-								} else if string(character) == "c" {
-									outputStr = fmt.Sprintf("%sj%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
-								} else if string(character) == "$" {
-									outputStr = fmt.Sprintf("%s#%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
-								}
-							}
-						}
-						if string(outputStr[charIndex-1]) == "'" {
-							if string(character) == "t" {
-								outputStr = fmt.Sprintf("%sd%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
-							} else if string(character) == "p" {
-								outputStr = fmt.Sprintf("%sb%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
-							} else if string(character) == "k" {
-								outputStr = fmt.Sprintf("%sg%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
-							} else if string(character) == "c" {
-								outputStr = fmt.Sprintf("%sj%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
-							} else if string(character) == "$" {
-								outputStr = fmt.Sprintf("%s#%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
-							}
-						}
-					} else if charIndex == len(outputStr)-1 {
-						if string(outputStr[charIndex-1]) == "'" {
-							if string(character) == "t" {
-								outputStr = fmt.Sprintf("%sd", string(outputStr[:charIndex]))
-							} else if string(character) == "p" {
-								outputStr = fmt.Sprintf("%sb", string(outputStr[:charIndex]))
-							} else if string(character) == "k" {
-								outputStr = fmt.Sprintf("%sg", string(outputStr[:charIndex]))
-							} else if string(character) == "c" {
-								outputStr = fmt.Sprintf("%sj", string(outputStr[:charIndex]))
-							} else if string(character) == "$" {
-								outputStr = fmt.Sprintf("%s#", string(outputStr[:charIndex]))
-							}
-						}
-					}
-				}
-				if IsConsonant(string(outputStr[0])) && IsConsonant(string(outputStr[1])) && !IsSonorant(string(outputStr[1])) {
-					outputStr = fmt.Sprintf("ɨ%s", outputStr)
-				}
-				if len(outputStr) > 3 {
-					if string(outputStr[len(outputStr)-3:]) == "eyi" {
-						outputStr = fmt.Sprintf("%seyy", string(outputStr[:len(outputStr)-3]))
-					} else if string(outputStr[len(outputStr)-3:]) == "ayi" {
-						outputStr = fmt.Sprintf("%sayy", string(outputStr[:len(outputStr)-3]))
-					}
-				}
-				outputStr = strings.Replace(outputStr, "$", "kw", -1)
-				outputStr = strings.Replace(outputStr, "#", "gw", -1)
-				outputStr = strings.Replace(outputStr, "c", "ch", -1)
-				outputStr = strings.Replace(outputStr, "ɨ", "ê", -1)
-				outputStr = strings.Replace(outputStr, "a'", "à", -1)
-				outputStr = strings.Replace(outputStr, "e'", "è", -1)
-				outputStr = strings.Replace(outputStr, "i'", "ì", -1)
-				outputStr = strings.Replace(outputStr, "o'", "ò", -1)
-				outputStr = strings.Replace(outputStr, "u'", "ù", -1)
-				InputArray[sliceIndex][stringIndex] = outputStr
-			}
+// converts one form between orthographies using the converter package, so that the conjugator and the OrthoConverter always agree
+// a form can hold several words (e.g. "mu teluisit") and comma-separated variants (e.g. "teluisit, teluisɨp")
+func convertForm(InputStr string, fromOrthography string, toOrthography string, syllabified bool) string {
+	if (fromOrthography == toOrthography && !syllabified) || InputStr == "*" || InputStr == "&&" || InputStr == "||" { // these are delineator characters and should not be converted
+		return InputStr
+	}
+	words := strings.Split(InputStr, " ")
+	for wordIndex, word := range words {
+		trimmedWord := strings.TrimSuffix(word, ",") // the comma between variants is put back after conversion
+		if trimmedWord == "" || trimmedWord == "*" { // a form that does not exist, after mu or ma'
+			continue
+		}
+		convertWord := converter.ConvertWord
+		if syllabified {
+			convertWord = converter.SyllabifyWord
+		}
+		convertedWord, convertErr := convertWord(trimmedWord, fromOrthography, toOrthography)
+		if convertErr != nil { // if the orthography is not one the converter knows, leave the word as it is
+			fmt.Println(convertErr)
+			continue
 		}
+		words[wordIndex] = fmt.Sprintf("%s%s", convertedWord, word[len(trimmedWord):])
 	}
-	return InputArray
+	return strings.Join(words, " ")
 }
 
 // this function returns the conjugation number and a model for the verb based off the InputVerb struct
@@ -548,12 +545,12 @@ func localizeOutput(languageChoice string, InputVerb Verb) (string, string, Disc
 			LocalOutputConjugation = "1"
 			LocalOutputModel = "pejila'sit"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.PejilasitDisclaimer // for splitting movement/non-movement verbs
+			LocalDisclaimer.DisclaimerText = template.HTML(language.PejilasitDisclaimer) // for splitting movement/non-movement verbs
 		} else if InputVerb.ConjugationVariant == "asik" {
 			LocalOutputConjugation = "1"
 			LocalOutputModel = "enqa'sik"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.EnqasikDisclaimer // for splitting movement/non-movement verbs and multiple future forms
+			LocalDisclaimer.DisclaimerText = template.HTML(language.EnqasikDisclaimer) // for splitting movement/non-movement verbs and multiple future forms
 		} else if InputVerb.ConjugationVariant == "ink" || InputVerb.ConjugationVariant == "cons" {
 			LocalOutputConjugation = "1"
 			LocalOutputModel = "pekisink"
@@ -561,7 +558,7 @@ func localizeOutput(languageChoice string, InputVerb Verb) (string, string, Disc
 			LocalOutputConjugation = "1"
 			LocalOutputModel = "maqatkwik"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.VIIDisclaimer // for multiple forms in the future (VII only)
+			LocalDisclaimer.DisclaimerText = template.HTML(language.VIIDisclaimer) // for multiple forms in the future (VII only)
 		} else if InputVerb.ConjugationVariant == "std" {
 			LocalOutputConjugation = "1"
 			LocalOutputModel = "teluisit"
@@ -574,7 +571,7 @@ func localizeOutput(languageChoice string, InputVerb Verb) (string, string, Disc
 			LocalOutputConjugation = "2"
 			LocalOutputModel = "pesaq"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.VIIDisclaimer // for multiple forms in the future (VII only)
+			LocalDisclaimer.DisclaimerText = template.HTML(language.VIIDisclaimer) // for multiple forms in the future (VII only)
 		} else if InputVerb.ConjugationVariant == "diph" {
 			LocalOutputConjugation = "1~2"
 			LocalOutputModel = "wekayk"
@@ -587,12 +584,12 @@ func localizeOutput(languageChoice string, InputVerb Verb) (string, string, Disc
 			LocalOutputConjugation = "3"
 			LocalOutputModel = "eliet"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.ElietDisclaimer // variant forms for land/water travel in the dual
+			LocalDisclaimer.DisclaimerText = template.HTML(language.ElietDisclaimer) // variant forms for land/water travel in the dual
 		} else if InputVerb.ConjugationVariant == "iaq" {
 			LocalOutputConjugation = "3"
 			LocalOutputModel = "ewniaq"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.EwniaqDisclaimer // variant forms for land/water travel in the dual (inanimate)
+			LocalDisclaimer.DisclaimerText = template.HTML(language.EwniaqDisclaimer) // variant forms for land/water travel in the dual (inanimate)
 		} else if InputVerb.ConjugationVariant == "uet" {
 			LocalOutputConjugation = "3"
 			LocalOutputModel = "teluet"
@@ -606,7 +603,7 @@ func localizeOutput(languageChoice string, InputVerb Verb) (string, string, Disc
 			LocalOutputConjugation = "3"
 			LocalOutputModel = "te'sipunqek"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.VIIDisclaimer // for multiple forms in the future (VII only)
+			LocalDisclaimer.DisclaimerText = template.HTML(language.VIIDisclaimer) // for multiple forms in the future (VII only)
 		} else if InputVerb.ConjugationVariant == "std" {
 			LocalOutputConjugation = "3"
 			LocalOutputModel = "ewi'kiket"
@@ -616,37 +613,37 @@ func localizeOutput(languageChoice string, InputVerb Verb) (string, string, Disc
 			LocalOutputConjugation = "4"
 			LocalOutputModel = "nestɨk"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.NestikDisclaimer // about the variant -kik/-mi'tij forms in the 3rd person plural
+			LocalDisclaimer.DisclaimerText = template.HTML(language.NestikDisclaimer) // about the variant -kik/-mi'tij forms in the 3rd person plural
 		} else if InputVerb.ConjugationVariant == "estem" {
 			LocalOutputConjugation = "4"
 			LocalOutputModel = "telte'k"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.NestikDisclaimer // about the variant -kik/-mi'tij forms in the 3rd person plural
+			LocalDisclaimer.DisclaimerText = template.HTML(language.NestikDisclaimer) // about the variant -kik/-mi'tij forms in the 3rd person plural
 		} else if InputVerb.ConjugationVariant == "cons" {
 			LocalOutputConjugation = "4"
 			LocalOutputModel = "nenk"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.NenkDisclaimer // some verbs in this group are inanimate subject only
+			LocalDisclaimer.DisclaimerText = template.HTML(language.NenkDisclaimer) // some verbs in this group are inanimate subject only
 		} else if InputVerb.ConjugationVariant == "istem" {
 			LocalOutputConjugation = "4"
 			LocalOutputModel = "ketkwi'k"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.NestikDisclaimer // about the variant -kik/-mi'tij forms in the 3rd person plural
+			LocalDisclaimer.DisclaimerText = template.HTML(language.NestikDisclaimer) // about the variant -kik/-mi'tij forms in the 3rd person plural
 		} else if InputVerb.ConjugationVariant == "eyk" {
 			LocalOutputConjugation = "4"
 			LocalOutputModel = "eyk"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.EykDisclaimer // explaining that eyk is animate, etek is inanimate
+			LocalDisclaimer.DisclaimerText = template.HTML(language.EykDisclaimer) // explaining that eyk is animate, etek is inanimate
 		} else if InputVerb.ConjugationVariant == "astem" {
 			LocalOutputConjugation = "4"
 			LocalOutputModel = "pewa'q"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.PewaqDisclaimer // about the variant -kik/-mi'tij forms in the 3rd person plural
+			LocalDisclaimer.DisclaimerText = template.HTML(language.PewaqDisclaimer) // about the variant -kik/-mi'tij forms in the 3rd person plural
 		} else if InputVerb.ConjugationVariant == "kstem" {
 			LocalOutputConjugation = "4"
 			LocalOutputModel = "ewi'kɨk"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.NestikDisclaimer // about the variant -kik/-mi'tij forms in the 3rd person plural
+			LocalDisclaimer.DisclaimerText = template.HTML(language.NestikDisclaimer) // about the variant -kik/-mi'tij forms in the 3rd person plural
 		} else if InputVerb.ConjugationVariant == "inan" {
 			LocalOutputConjugation = "4~5"
 			LocalOutputModel = "telamu'k"
@@ -654,14 +651,14 @@ func localizeOutput(languageChoice string, InputVerb Verb) (string, string, Disc
 			LocalOutputConjugation = "4"
 			LocalOutputModel = "kesatk"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.NestikDisclaimer // about the variant -kik/-mi'tij forms in the 3rd person plural
+			LocalDisclaimer.DisclaimerText = template.HTML(language.NestikDisclaimer) // about the variant -kik/-mi'tij forms in the 3rd person plural
 		}
 	} else if InputVerb.Conjugation == 5 {
 		if InputVerb.ConjugationVariant == "kuk" {
 			LocalOutputConjugation = "5"
 			LocalOutputModel = "ketuk"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.KetukDisclaimer // some verbs in this group are inanimate subject only
+			LocalDisclaimer.DisclaimerText = template.HTML(language.KetukDisclaimer) // some verbs in this group are inanimate subject only
 		} else if InputVerb.ConjugationVariant == "std" {
 			LocalOutputConjugation = "5"
 			LocalOutputModel = "mena'toq"
@@ -674,7 +671,7 @@ func localizeOutput(languageChoice string, InputVerb Verb) (string, string, Disc
 			LocalOutputConjugation = "6"
 			LocalOutputModel = "pesa'tl"
 			LocalDisclaimer.Defined = true
-			LocalDisclaimer.DisclaimerText = language.PesatlDisclaimer // some verbs of this group have -a- stems, some have -e- stems
+			LocalDisclaimer.DisclaimerText = template.HTML(language.PesatlDisclaimer) // some verbs of this group have -a- stems, some have -e- stems
 		} else if InputVerb.ConjugationVariant == "ibar" {
 			LocalOutputConjugation = "6"
 			LocalOutputModel = "e'natl"
@@ -692,28 +689,64 @@ func localizeOutput(languageChoice string, InputVerb Verb) (string, string, Disc
 	return LocalOutputConjugation, LocalOutputModel, LocalDisclaimer
 }
 
+// the tags of the html written for the page, e.g. <i> and <br>
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// turns html written for the page into plain text for the json output: line breaks become newlines, other tags are removed, and entities are unescaped
+// the input in an error message was escaped, so it comes out as it was typed
+func plainText(pageHTML template.HTML) string {
+	plainStr := strings.ReplaceAll(string(pageHTML), "<br>", "\n")
+	return html.UnescapeString(htmlTag.ReplaceAllString(plainStr, ""))
+}
+
+// returns the message shown for an error in the chosen language, and its code for the json output
+func localizeError(languageChoice string, err error) (template.HTML, string) {
+	language := LocalizationDictionary[languageChoice]
+	var InputErr *converter.InputError
+	errors.As(err, &InputErr)
+	switch {
+	case errors.Is(err, ErrUnknownEnding):
+		return template.HTML(language.ErrorUnknownEnding), "unknown_ending"
+	case errors.Is(err, converter.ErrInputTooLong) && InputErr != nil:
+		return template.HTML(fmt.Sprintf(language.ErrorInputTooLong, InputErr.Length, converter.MaxWordLength)), converter.ErrorCode(err)
+	case errors.Is(err, converter.ErrInvalidCharacter) && InputErr != nil:
+		character := html.EscapeString(string(InputErr.Character))
+		if !unicode.IsGraphic(InputErr.Character) || unicode.IsSpace(InputErr.Character) { // e.g. a space between two words
+			character = fmt.Sprintf("%U", InputErr.Character)
+		}
+		return template.HTML(fmt.Sprintf(language.ErrorInvalidCharacter, character, html.EscapeString(converter.OrthographyNames[InputErr.Orthography]))), converter.ErrorCode(err)
+	case errors.Is(err, converter.ErrEmptyInput):
+		return template.HTML(language.ErrorEmptyInput), converter.ErrorCode(err)
+	case errors.Is(err, converter.ErrUnknownOrthography):
+		return template.HTML(language.ErrorUnknownOrthography), converter.ErrorCode(err)
+	}
+	return template.HTML(html.EscapeString(err.Error())), converter.ErrorCode(err)
+}
+
 // this function returns the proper strings for titles, buttons, tenses, subject persons, etc. based on language
 func localize(page MainPage, languageChoice string) MainPage {
 	// get localization strings
 	language := LocalizationDictionary[languageChoice]
 	page.Title = language.PageTitle
-	page.OrthographyTooltip = language.OrthographyTooltip
-	page.EntryPrompt = language.EntryPrompt
+	page.OrthographyTooltip = template.HTML(language.OrthographyTooltip)
+	page.EntryPrompt = template.HTML(language.EntryPrompt)
 	page.SummaryDetails = language.SummaryDetails
 	page.LanguageFieldLabel = language.LanguageFieldLabel
 	page.English = language.English
 	page.Mikmaw = language.Mikmaw
 	page.French = language.French
-	page.ConjugateButton = language.ConjugateButton
+	page.ConjugateButton = template.HTML(language.ConjugateButton)
 	page.OutputConjugationTitle = language.OutputConjugation
 	page.OutputModelTitle = language.OutputModel
 	page.OutputTitle = language.OutputTitle
 	page.InfoTitle = language.InfoTitle
 	page.HelpTitle = language.HelpTitle
-	page.HelpField = language.HelpField
+	page.HelpField = template.HTML(language.HelpField)
 	page.SourceTitle = language.SourceTitle
-	page.SourceField = language.SourceField
+	page.SourceField = template.HTML(language.SourceField)
 	page.OrthographyRadioButtonTitle = language.OrthographyRadioButtonTitle
+	page.SideBySideTitle = language.SideBySideTitle
+	page.SyllablesTitle = language.SyllablesTitle
 	page.LinksTitle = language.LinksTitle
 	page.HomePage = language.HomePage
 	page.ContactMe = language.ContactMe
@@ -722,18 +755,17 @@ func localize(page MainPage, languageChoice string) MainPage {
 }
 
 // this function passes to other functions to return an InputVerb of type Verb and a ConjugationArray of a multidimensional string slice
-func readoutVerb(InputStr string) ([][]string, Verb) {
+func readoutVerb(InputStr string) ([][]string, Verb, error) {
 	var ConjugationArray [][]string       // a composite literal of strings to hold the conjugated forms
 	InputVerb, err := parseVerb(InputStr) // parse the verb stem
 	if err != nil {                       // if the parseVerb function throws an error
-		fmt.Println(err)
-		return ConjugationArray, InputVerb
+		return ConjugationArray, InputVerb, err
 	}
 	InputVerb.ContractedStem = contractStem(InputVerb.Stem, InputVerb.Conjugation) // get the contracted stem
 
 	ConjugationArray = conjugateVerb(InputVerb) // initialize the composite literal multidimensional string slice
 
-	return ConjugationArray, InputVerb
+	return ConjugationArray, InputVerb, nil
 }
 
 // the rows and columns need to be switched:
@@ -1381,7 +1413,7 @@ func pluralInanimateForms(InputArray [][]string) [][]string {
 						pluralForm = fmt.Sprintf("%sanl", form) // the first person singular takes -anl
 					}
 				} else {
-					if string(form[len(form)-1]) == "l" { // if a form ends in -l, the plural is the same
+					if strings.HasSuffix(form, "l") { // if a form ends in -l, the plural is the same
 						pluralForm = form
 					} else {
 						if strings.Contains(form, ",") { // if there is a comma, i.e. if there are multiple variants
@@ -1409,9 +1441,9 @@ func pluralInanimateForms(InputArray [][]string) [][]string {
 					splitForms := strings.Split(form, ", ")
 					pluralForm = fmt.Sprintf("%sl, %sl", splitForms[0], splitForms[1]) // the first person singular takes -anl
 				} else {
-					if string(form[len(form)-1]) == "n" || string(form[len(form)-1]) == "k" { // if a form ends in -n or -k
+					if strings.HasSuffix(form, "n") || strings.HasSuffix(form, "k") { // if a form ends in -n or -k
 						pluralForm = fmt.Sprintf("%sl", form)
-					} else if string(form[len(form)-1]) == "l" { // if the form ends in -l, the plural is the same
+					} else if strings.HasSuffix(form, "l") { // if the form ends in -l, the plural is the same
 						pluralForm = form
 					} else {
 						pluralForm = fmt.Sprintf("%snl", form) // else, append -nl (for the past, like teluisiyekɨp-nl)
@@ -1555,16 +1587,39 @@ func imperativeNegativeFormsVII(InputForms []string) []string { // VII verbs nee
 // this function will create a type Verb by recognizing the group of the input stem
 // called by readoutVerb
 func parseVerb(InputStr string) (Verb, error) {
+	var InputVerb Verb // define an instance of the Verb struct
+
+	// a verb is a single francis-smith word: commas, spaces, digits, etc. would be taken for the separators between forms
+	validateErr := converter.ValidateWord(InputStr, "francissmith")
+	if validateErr != nil {
+		return InputVerb, validateErr
+	}
+
+	InputStr = strings.ToLower(InputStr) // make it lowercase
+
+	// compose diacritics typed as separate characters, and read lookalike apostrophes and barred i's as the real ones,
+	// so that a verb gives the same conjugation however it was typed
+	InputStr = converter.NormalizeUnicode(InputStr)
+
+	// the character substitution table shared with the converter, e.g. the curly apostrophe of apple keyboards for '
+	InputStr = converter.Substitute(InputStr, "francissmith")
+
 	// replace ɨ with *
 	InputStr = strings.Replace(InputStr, "ɨ", "*", -1)
-	InputStr = strings.ToLower(InputStr) // make it lowercase
 
-	//on apple keyboards, they use the curly apostrophe. this must be replaced
-	InputStr = strings.Replace(InputStr, "’", "'", -1)
+	InputVerb, parseErr := parseVerbEnding(InputStr)
+	if parseErr == nil && InputVerb.Stem == "" { // a verb that is nothing but an ending, e.g. "at", would be conjugated with no stem at all
+		return Verb{}, fmt.Errorf("%w: %s", ErrUnknownEnding, strings.Replace(InputStr, "*", "ɨ", -1))
+	}
+	return InputVerb, parseErr
+}
 
+// recognizes the group of a verb by its ending, once parseVerb has cleaned it up (with ɨ as *)
+func parseVerbEnding(InputStr string) (Verb, error) {
 	var InputVerb Verb // define an instance of the Verb struct
-	var Ending string  // for storing the ending of the InputStr
-	var FinalInt int   // for how many characters you are looking at the end of a verb
+
+	var Ending string // for storing the ending of the InputStr
+	var FinalInt int  // for how many characters you are looking at the end of a verb
 
 	// exceptions
 	// i had thought about putting e.g. "etek" here, as an exception to "eyk", etc.,
@@ -1585,12 +1640,12 @@ func parseVerb(InputStr string) (Verb, error) {
 
 	// only 1 five letter ending that stands alone
 	FinalInt = 5
-	if len(InputStr) > 4 { // strings with length less than 5 will throw an error
+	if utf8.RuneCountInString(InputStr) > 4 { // strings with fewer than 5 characters cannot have this ending
 		Ending = getVerbEnding(InputStr, FinalInt)
 	}
 	if Ending == "a's*k" { // first conjugation inanimate verbs in "a'sɨk" (orthographical/Listuguj variant of "a'sik")
 		InputStr = strings.Replace(InputStr, "*", "ɨ", -1) // convert the stars back to ɨ
-		InputVerb.Stem = getVerbStem(InputStr, FinalInt+1) // ɨ counts as two characters?
+		InputVerb.Stem = getVerbStem(InputStr, FinalInt)
 		InputVerb.Conjugation = 1
 		InputVerb.ConjugationVariant = "asik"
 		InputVerb.Type = VII
@@ -1599,7 +1654,7 @@ func parseVerb(InputStr string) (Verb, error) {
 
 	// do 4 letter verb endings
 	FinalInt = 4
-	if len(InputStr) > 3 { // strings with length less than 4 will throw an error
+	if utf8.RuneCountInString(InputStr) > 3 { // strings with fewer than 4 characters cannot have this ending
 		Ending = getVerbEnding(InputStr, FinalInt)
 	}
 	if Ending == "a'tl" {
@@ -1617,7 +1672,7 @@ func parseVerb(InputStr string) (Verb, error) {
 
 	// now do 3 letter verb endings
 	FinalInt = 3
-	if len(InputStr) > 2 { // strings with length less than 3 will throw an error
+	if utf8.RuneCountInString(InputStr) > 2 { // strings with fewer than 3 characters cannot have this ending
 		Ending = getVerbEnding(InputStr, FinalInt)
 	}
 	if Ending == "a't" { // second conjugation verbs with a long vowel
@@ -1669,7 +1724,7 @@ func parseVerb(InputStr string) (Verb, error) {
 		return InputVerb, nil
 	} else if Ending == "t*k" { // fourth conjugation verbs that end in An -t, with an intervening schwa
 		InputStr = strings.Replace(InputStr, "*", "ɨ", -1) // convert the stars back to ɨ
-		InputVerb.Stem = getVerbStem(InputStr, FinalInt+1) // ɨ counts as two characters?
+		InputVerb.Stem = getVerbStem(InputStr, FinalInt)
 		InputVerb.Conjugation = 4
 		InputVerb.ConjugationVariant = "ibar"
 		InputVerb.Type = VTI
@@ -1718,7 +1773,8 @@ func parseVerb(InputStr string) (Verb, error) {
 			InputVerb.Conjugation = 6
 			// check if the verb has a long vowel + consonant before the personal agreement ending, e.g. e'natl
 			// these verbs require a schwa inserted for phonotactic reasons
-			if IsConsonant(string(InputVerb.Stem[len(InputVerb.Stem)-1])) && string(InputVerb.Stem[len(InputVerb.Stem)-2]) == "'" {
+			StemRunes := []rune(InputVerb.Stem)
+			if len(StemRunes) > 1 && IsConsonant(string(StemRunes[len(StemRunes)-1])) && StemRunes[len(StemRunes)-2] == '\'' {
 				InputVerb.ConjugationVariant = "ibar"
 			} else {
 				InputVerb.ConjugationVariant = "std"
@@ -1736,7 +1792,7 @@ func parseVerb(InputStr string) (Verb, error) {
 
 	// then do 2 letter verb endings
 	FinalInt = 2
-	if len(InputStr) > 1 { // strings with length 1 will throw an error
+	if utf8.RuneCountInString(InputStr) > 1 { // strings of 1 character cannot have this ending
 		Ending = getVerbEnding(InputStr, FinalInt)
 	}
 	if Ending == "it" { // Pacifique's first conjugation
@@ -1794,7 +1850,7 @@ func parseVerb(InputStr string) (Verb, error) {
 		InputVerb.ConjugationVariant = "std"
 		InputVerb.Type = VTI
 		return InputVerb, nil
-	} else if string(Ending[1]) == "k" && IsConsonant(string(Ending[0])) && string(Ending[0]) != "t" { // more of Pacifique's fourth conjugation
+	} else if EndingRunes := []rune(Ending); len(EndingRunes) == 2 && EndingRunes[1] == 'k' && IsConsonant(string(EndingRunes[0])) && EndingRunes[0] != 't' { // more of Pacifique's fourth conjugation
 		InputStr = strings.Replace(InputStr, "*", "ɨ", -1) // convert the stars back to ɨ
 		FinalInt = 1
 		InputVerb.Stem = getVerbStem(InputStr, FinalInt)
@@ -1810,9 +1866,9 @@ func parseVerb(InputStr string) (Verb, error) {
 			InputVerb.ConjugationVariant = "cons"
 		}
 		return InputVerb, nil
-	} else if Ending == "*k" && string(InputStr[len(InputStr)-1:]) != "t" { // fourth conjugation verbs with stems in -kɨk
+	} else if Ending == "*k" && !strings.HasSuffix(InputStr, "t") { // fourth conjugation verbs with stems in -kɨk
 		InputStr = strings.Replace(InputStr, "*", "ɨ", -1) // convert the stars back to ɨ
-		InputVerb.Stem = getVerbStem(InputStr, FinalInt+1) // ɨ counts as two characters?
+		InputVerb.Stem = getVerbStem(InputStr, FinalInt)
 		InputVerb.Conjugation = 4
 		InputVerb.ConjugationVariant = "kstem"
 		InputVerb.Type = VTI
@@ -1855,68 +1911,80 @@ func parseVerb(InputStr string) (Verb, error) {
 		return InputVerb, nil
 	}
 
-	return InputVerb, errors.New("Verb Unrecognized")
+	return InputVerb, fmt.Errorf("%w: %s", ErrUnknownEnding, InputStr)
 }
 
 // this returns the input string minus the last FinalInt characters (i.e. the stem)
+// characters are counted as runes, so that ɨ, ô, ă, etc. count as one character each
 func getVerbStem(InputStr string, FinalInt int) string {
-	OutputStr := InputStr[:len(InputStr)-FinalInt] // gives InputStr minues the last FinalInt characters
+	InputRunes := []rune(InputStr)
+	if FinalInt > len(InputRunes) { // a verb that is all ending has no stem
+		return ""
+	}
+	OutputStr := string(InputRunes[:len(InputRunes)-FinalInt]) // gives InputStr minus the last FinalInt characters
 	return OutputStr
 }
 
-// this returns the last FinalInt characters of the input string (i.e. the ending of the verb)
+// this returns the last FinalInt characters of the input string (i.e. the ending of the verb), or all of it if it is shorter
 func getVerbEnding(InputStr string, FinalInt int) string {
-	OutputStr := InputStr[len(InputStr)-FinalInt:] // gives the last FinalInt characters of InputStr
+	InputRunes := []rune(InputStr)
+	if FinalInt > len(InputRunes) {
+		return InputStr
+	}
+	OutputStr := string(InputRunes[len(InputRunes)-FinalInt:]) // gives the last FinalInt characters of InputStr
 	return OutputStr
 }
 
 // this returns a contracted stem — verbs with "e" in the first syllable have it removed, and there are different phonotactic consequences for this
+// the stem is looked at one character (rune) at a time, so that ɨ and other letters outside ascii are single characters
 func contractStem(InputStr string, Conjugation int) string { // return the contracted stem for use in the future, etc.
-	var OutputStr string
-	if len(InputStr) == 1 { // strings of length 1 have to be caught immediately
+	InputRunes := []rune(InputStr)
+	var OutputRunes []rune
+	if len(InputRunes) == 0 { // a verb that is all ending has no stem to contract
+		return ""
+	}
+	if len(InputRunes) == 1 { // strings of length 1 have to be caught immediately
 		if InputStr != "e" { // if the only letter of the "stem" is not e, then return that
-			OutputStr = InputStr
-			return OutputStr
+			return InputStr
 		} else { // if the only letter is an e, return a blank string
-			OutputStr = ""
-			return OutputStr
+			return ""
 		}
 	}
-	if string(InputStr[0]) == "e" && IsConsonant(string(InputStr[1])) {
-		OutputStr = InputStr[1:]
-		if string(OutputStr[0]) == "y" { // if the first character is y, turn this into i'. e.g. ey- => y- => i'-
-			OutputStr = fmt.Sprintf("i'%s", OutputStr[1:])
+	if InputRunes[0] == 'e' && IsConsonant(string(InputRunes[1])) {
+		OutputRunes = InputRunes[1:]
+		if OutputRunes[0] == 'y' { // if the first character is y, turn this into i'. e.g. ey- => y- => i'-
+			OutputRunes = append([]rune("i'"), OutputRunes[1:]...)
 		}
-	} else if string(InputStr[1]) == "e" && IsConsonant(string(InputStr[2])) { // if the second character is e and the third character is a consonant
+	} else if len(InputRunes) > 2 && InputRunes[1] == 'e' && IsConsonant(string(InputRunes[2])) { // if the second character is e and the third character is a consonant
 		// should not matter if the first character is a consonant or vowel, since if e is the second character, the first should be a consonant anyways
-		OutputStr = fmt.Sprintf("%s%s", string(InputStr[0]), InputStr[2:])
-		if string(OutputStr[0]) == "y" { // see above; if the first character is y, turn this into i'. e.g. ey- => y- => i'-
-			OutputStr = fmt.Sprintf("i'%s", OutputStr[1:])
+		OutputRunes = append([]rune{InputRunes[0]}, InputRunes[2:]...)
+		if OutputRunes[0] == 'y' { // see above; if the first character is y, turn this into i'. e.g. ey- => y- => i'-
+			OutputRunes = append([]rune("i'"), OutputRunes[1:]...)
 		}
-		if len(OutputStr) > 2 { //handle stems shorter than two differently
-			if IsConsonant(string(OutputStr[0])) && IsPlosive(string(OutputStr[1])) && IsPlosive(string(OutputStr[2])) {
+		if len(OutputRunes) > 2 { //handle stems shorter than two differently
+			if IsConsonant(string(OutputRunes[0])) && IsPlosive(string(OutputRunes[1])) && IsPlosive(string(OutputRunes[2])) {
 				// if the first character is a consonant, the second is a plosive, and the third is a plosive
-				if IsPlosive(string(OutputStr[0])) { // if the first is a plosive
-					OutputStr = fmt.Sprintf("%sɨ%s", OutputStr[:1], OutputStr[1:])
+				if IsPlosive(string(OutputRunes[0])) { // if the first is a plosive
+					OutputRunes = []rune(fmt.Sprintf("%sɨ%s", string(OutputRunes[:1]), string(OutputRunes[1:])))
 				} else { // if the first is not a plosive (therefore must be a sonorant, a consonant that is not a plosive)
-					OutputStr = fmt.Sprintf("%sɨ%s", OutputStr[:2], OutputStr[2:])
+					OutputRunes = []rune(fmt.Sprintf("%sɨ%s", string(OutputRunes[:2]), string(OutputRunes[2:])))
 				}
 			}
 		} else { // stems that are shorter than two characters
-			if IsConsonant(string(OutputStr[0])) && IsPlosive(string(OutputStr[1])) && Conjugation != 6 && Conjugation != 7 {
+			if IsConsonant(string(OutputRunes[0])) && IsPlosive(string(OutputRunes[1])) && Conjugation != 6 && Conjugation != 7 {
 				// if the first character is a consonant, and the second is a plosive
 				if Conjugation == 1 || Conjugation == 2 || Conjugation == 3 { // first 3 conjugations involve vowels, so no need for a schwa if the stem is 2 characters.
-					OutputStr = fmt.Sprintf("%s%s", OutputStr[:2], OutputStr[2:])
+					OutputRunes = OutputRunes[:2]
 				} else {
 					// in VTA verbs (conj. 6, 7), the affixes contain vowels that do not necessitate the insertion of a schwa
-					OutputStr = fmt.Sprintf("%sɨ", OutputStr)
+					OutputRunes = append(OutputRunes, 'ɨ')
 				}
-			} else if OutputStr[0] == OutputStr[1] { // if the first and second characters are equal, e.g. for nen- => nn- => nɨn-
-				OutputStr = fmt.Sprintf("%sɨ%s", string(OutputStr[0]), string(OutputStr[1]))
+			} else if OutputRunes[0] == OutputRunes[1] { // if the first and second characters are equal, e.g. for nen- => nn- => nɨn-
+				OutputRunes = []rune{OutputRunes[0], 'ɨ', OutputRunes[1]}
 			}
 		}
 	} else {
-		OutputStr = InputStr
+		OutputRunes = InputRunes
 	} // if no contraction can be made, the "contracted" stem is the same as the uncontracted one
-	return OutputStr
+	return string(OutputRunes)
 }
@@ -0,0 +1,252 @@
+// checks the OrthoConverter against the converters bescherelle had of its own before it used the OrthoConverter,
+// which wrote listuguj and metallic with their own rules (e.g. the schwa metallic writes before two consonants at the beginning of a word)
+// every form of every verb is converted into each orthography both ways, and read back into francis-smith both ways,
+// so that where they disagree, which of them is wrong can be decided before their rules are unified
+// the old converters are kept below as they were, as the reference side of the check
+// cmd/crosscheck prints what CrossCheck finds
+
+package bescherelle
+
+import (
+	"conjugator/converter"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Disagreement struct { // a form that bescherelle and the converter do not write or read back the same way, or that is not read back as it was
+	Verb        string
+	Orthography string
+	Direction   string // "to" if the form was converted into the orthography, "from" if it was read back into francis-smith
+	Input       string // the form that was converted
+	Expected    string // the francis-smith form, when it is read back
+	Bescherelle string // what the old bescherelle converters convert the form to
+	Converter   string // what the converter converts the form to, as text
+	Known       bool   // both read it back the same, and it only differs from the form in what the orthography writes like something else (see converter.Orthography.Losses)
+}
+
+// the orthographies bescherelle converted to and from itself, which are the only ones CrossCheck can check
+var CrossCheckOrthographies = []string{"listuguj", "metallic"}
+
+// the old bescherelle converters of every orthography in CrossCheckOrthographies
+var (
+	bescherelleEncoders = map[string]func([][]string) [][]string{"listuguj": convertFrancisSmithtoListuguj, "metallic": convertFrancisSmithtoMetallic}
+	bescherelleDecoders = map[string]func(string) string{"listuguj": convertListugujtoFrancisSmith, "metallic": convertMetallictoFrancisSmith}
+)
+
+var ErrNoBescherelleConverter = errors.New("bescherelle had no converter of its own for this orthography")
+
+// CrossCheck conjugates every verb and converts all its forms into every orthography and back, with the old bescherelle converters
+// and with the OrthoConverter, and returns every form they do not agree on
+// both read back what the converter wrote, so that a form they write differently is only counted once, and what they read back also has to be the form it was,
+// unless the only difference is something the orthography writes like something else: then it is returned as Known
+// the dictionaries have to be loaded first (see LoadDictionaries)
+func CrossCheck(verbs []string, orthographies []string) ([]Disagreement, error) {
+	var Disagreements []Disagreement
+	for _, orthographyChoice := range orthographies {
+		if _, found := converter.OrthographyNames[orthographyChoice]; !found {
+			return nil, fmt.Errorf("%w: %s", converter.ErrUnknownOrthography, orthographyChoice)
+		}
+		if _, found := bescherelleEncoders[orthographyChoice]; !found {
+			return nil, fmt.Errorf("%w: %s", ErrNoBescherelleConverter, orthographyChoice)
+		}
+	}
+	for _, verb := range verbs {
+		ConjugationArray, _, readoutErr := readoutVerb(verb)
+		if readoutErr != nil {
+			return nil, readoutErr
+		}
+		checkedForms := make(map[string]bool) // forms that are in more than one table are only checked once
+		for _, tense := range ConjugationArray {
+			for _, form := range tense {
+				if checkedForms[form] || form == "" || form == "*" || form == "&&" || form == "||" { // delineators, and forms that do not exist
+					continue
+				}
+				checkedForms[form] = true
+				for _, orthographyChoice := range orthographies {
+					Disagreements = append(Disagreements, crossCheckForm(verb, form, orthographyChoice)...)
+				}
+			}
+		}
+	}
+	return Disagreements, nil
+}
+
+// converts one francis-smith form into an orthography and back, both ways, and returns what does not agree
+func crossCheckForm(verb string, form string, orthographyChoice string) []Disagreement {
+	var Disagreements []Disagreement
+	bescherelleForm := bescherelleEncoders[orthographyChoice]([][]string{{form}})[0][0]
+	converterForm, _ := converter.Convert(form, "francissmith", orthographyChoice)
+	if bescherelleForm != converterForm {
+		Disagreements = append(Disagreements, Disagreement{verb, orthographyChoice, "to", form, "", bescherelleForm, converterForm, false})
+	}
+	bescherelleBack := strings.ReplaceAll(bescherelleDecoders[orthographyChoice](converterForm), "*", "ɨ") // bescherelle read the schwa as *, which parseVerb took as ɨ
+	converterBack, _ := converter.Convert(converterForm, orthographyChoice, "francissmith")
+	if bescherelleBack != converterBack || converterBack != form {
+		known := bescherelleBack == converterBack && converter.SameOnceCollapsed(unifiedForm(form), unifiedForm(converterBack), orthographyChoice)
+		Disagreements = append(Disagreements, Disagreement{verb, orthographyChoice, "from", converterForm, form, bescherelleBack, converterBack, known})
+	}
+	return Disagreements
+}
+
+// a francis-smith form in unified orthography, one word at a time (e.g. "mu teluisik")
+func unifiedForm(form string) string {
+	var unifiedWords []string
+	for _, word := range strings.Fields(form) {
+		unifiedWord, _ := converter.NormalizeWord(word, "francissmith")
+		unifiedWords = append(unifiedWords, unifiedWord)
+	}
+	return strings.Join(unifiedWords, " ")
+}
+
+// converts anything written in listuguj orthography into francis-smith
+func convertListugujtoFrancisSmith(InputStr string) string {
+	OutputStr := strings.ToLower(InputStr)
+	// everything below is just replacing character combinations with others
+	// the output string is in francis-smith
+	OutputStr = strings.Replace(OutputStr, "g", "k", -1)
+	OutputStr = strings.Replace(OutputStr, "ai", "ay", -1)
+	OutputStr = strings.Replace(OutputStr, "a'i", "a'y", -1)
+	OutputStr = strings.Replace(OutputStr, "ei", "ey", -1)
+	OutputStr = strings.Replace(OutputStr, "e'i", "e'y", -1)
+	// replace all apostrophes after consonants as schwas, but use the escape character "*"
+	OutputStr = strings.Replace(OutputStr, "j'", "j*", -1)
+	OutputStr = strings.Replace(OutputStr, "k'", "k*", -1)
+	OutputStr = strings.Replace(OutputStr, "m'", "m*", -1)
+	OutputStr = strings.Replace(OutputStr, "n'", "n*", -1)
+	OutputStr = strings.Replace(OutputStr, "p'", "p*", -1)
+	OutputStr = strings.Replace(OutputStr, "q'", "q*", -1)
+	OutputStr = strings.Replace(OutputStr, "s'", "s*", -1)
+	OutputStr = strings.Replace(OutputStr, "t'", "t*", -1)
+	return OutputStr
+}
+
+// converts anything written in metallic orthography into francis-smith
+func convertMetallictoFrancisSmith(InputStr string) string {
+	OutputStr := strings.ToLower(InputStr)
+	// everything below is just replacing character combinations with others
+	// the output string is in francis-smith
+	OutputStr = strings.Replace(OutputStr, "b", "p", -1)
+	OutputStr = strings.Replace(OutputStr, "d", "t", -1)
+	OutputStr = strings.Replace(OutputStr, "ch", "j", -1)
+	OutputStr = strings.Replace(OutputStr, "g", "k", -1)
+	OutputStr = strings.Replace(OutputStr, "ê", "*", -1) // replace schwa with star
+	OutputStr = strings.Replace(OutputStr, "à", "a'", -1)
+	OutputStr = strings.Replace(OutputStr, "è", "e'", -1)
+	OutputStr = strings.Replace(OutputStr, "ì", "i'", -1)
+	OutputStr = strings.Replace(OutputStr, "ò", "o'", -1)
+	OutputStr = strings.Replace(OutputStr, "ù", "u'", -1)
+	return OutputStr
+}
+
+// converts anything written in francis-smith into listuguj
+func convertFrancisSmithtoListuguj(InputArray [][]string) [][]string {
+	for sliceIndex := range InputArray {
+		for stringIndex, str := range InputArray[sliceIndex] {
+			outputStr := str
+			outputStr = strings.Replace(outputStr, "k", "g", -1)
+			outputStr = strings.Replace(outputStr, "ɨ", "'", -1)
+			outputStr = strings.Replace(outputStr, "y", "i", -1)
+			// converting "y" to "i" in strings of "yi" will lead to "ii"
+			outputStr = strings.Replace(outputStr, "ii", "i", -1)
+			InputArray[sliceIndex][stringIndex] = outputStr
+		}
+	}
+	return InputArray
+}
+
+// converts anything written in francis-smith into metallic
+func convertFrancisSmithtoMetallic(InputArray [][]string) [][]string {
+	for sliceIndex := range InputArray {
+		for stringIndex, str := range InputArray[sliceIndex] {
+			outputStr := str
+			outputStr = strings.Replace(outputStr, "kw", "$", -1)
+			outputStr = strings.Replace(outputStr, "j", "c", -1)
+			outputStr = strings.Replace(outputStr, "cc", "tc", -1)
+			outputStr = strings.Replace(outputStr, "ɨ", "ê", -1)
+			if len(outputStr) > 1 {
+				for charIndex, character := range outputStr {
+					if charIndex == 0 {
+						if !IsConsonant(string(outputStr[charIndex+1])) || IsSonorant(string(outputStr[charIndex+1])) {
+							if string(character) == "t" {
+								outputStr = fmt.Sprintf("d%s", string(outputStr[charIndex+1:]))
+							} else if string(character) == "p" {
+								outputStr = fmt.Sprintf("b%s", string(outputStr[charIndex+1:]))
+							} else if string(character) == "k" {
+								outputStr = fmt.Sprintf("g%s", string(outputStr[charIndex+1:]))
+							} else if string(character) == "$" {
+								outputStr = fmt.Sprintf("#%s", string(outputStr[charIndex+1:]))
+							} else if string(character) == "c" {
+								outputStr = fmt.Sprintf("j%s", string(outputStr[charIndex+1:]))
+							}
+						}
+					} else if charIndex != len(outputStr)-1 {
+						if string(outputStr[charIndex+1]) != "," {
+							if (!IsConsonant(string(outputStr[charIndex+1])) || IsSonorant(string(outputStr[charIndex+1]))) && !IsConsonant(string(outputStr[charIndex-1])) {
+								if string(character) == "t" {
+									outputStr = fmt.Sprintf("%sd%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
+								} else if string(character) == "p" {
+									outputStr = fmt.Sprintf("%sb%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
+								} else if string(character) == "k" {
+									outputStr = fmt.Sprintf("%sg%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
+								} else if string(character) == "c" {
+									outputStr = fmt.Sprintf("%sj%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
+								} else if string(character) == "$" {
+									outputStr = fmt.Sprintf("%s#%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
+								}
+							}
+						}
+						if string(outputStr[charIndex-1]) == "'" {
+							if string(character) == "t" {
+								outputStr = fmt.Sprintf("%sd%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
+							} else if string(character) == "p" {
+								outputStr = fmt.Sprintf("%sb%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
+							} else if string(character) == "k" {
+								outputStr = fmt.Sprintf("%sg%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
+							} else if string(character) == "c" {
+								outputStr = fmt.Sprintf("%sj%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
+							} else if string(character) == "$" {
+								outputStr = fmt.Sprintf("%s#%s", string(outputStr[:charIndex]), string(outputStr[charIndex+1:]))
+							}
+						}
+					} else if charIndex == len(outputStr)-1 {
+						if string(outputStr[charIndex-1]) == "'" {
+							if string(character) == "t" {
+								outputStr = fmt.Sprintf("%sd", string(outputStr[:charIndex]))
+							} else if string(character) == "p" {
+								outputStr = fmt.Sprintf("%sb", string(outputStr[:charIndex]))
+							} else if string(character) == "k" {
+								outputStr = fmt.Sprintf("%sg", string(outputStr[:charIndex]))
+							} else if string(character) == "c" {
+								outputStr = fmt.Sprintf("%sj", string(outputStr[:charIndex]))
+							} else if string(character) == "$" {
+								outputStr = fmt.Sprintf("%s#", string(outputStr[:charIndex]))
+							}
+						}
+					}
+				}
+				if IsConsonant(string(outputStr[0])) && IsConsonant(string(outputStr[1])) && !IsSonorant(string(outputStr[1])) {
+					outputStr = fmt.Sprintf("ɨ%s", outputStr)
+				}
+				if len(outputStr) > 3 {
+					if string(outputStr[len(outputStr)-3:]) == "eyi" {
+						outputStr = fmt.Sprintf("%seyy", string(outputStr[:len(outputStr)-3]))
+					} else if string(outputStr[len(outputStr)-3:]) == "ayi" {
+						outputStr = fmt.Sprintf("%sayy", string(outputStr[:len(outputStr)-3]))
+					}
+				}
+				outputStr = strings.Replace(outputStr, "$", "kw", -1)
+				outputStr = strings.Replace(outputStr, "#", "gw", -1)
+				outputStr = strings.Replace(outputStr, "c", "ch", -1)
+				outputStr = strings.Replace(outputStr, "ɨ", "ê", -1)
+				outputStr = strings.Replace(outputStr, "a'", "à", -1)
+				outputStr = strings.Replace(outputStr, "e'", "è", -1)
+				outputStr = strings.Replace(outputStr, "i'", "ì", -1)
+				outputStr = strings.Replace(outputStr, "o'", "ò", -1)
+				outputStr = strings.Replace(outputStr, "u'", "ù", -1)
+				InputArray[sliceIndex][stringIndex] = outputStr
+			}
+		}
+	}
+	return InputArray
+}
@@ -0,0 +1,209 @@
+// evaluates the conjugator against attested forms, e.g. from spreadsheets of forms given by elders or found in published grammars
+// each attested form is a verb, a tense and a person, and how the form is written in an orthography
+// the verb is conjugated the way the page conjugates it in that orthography, and the form in the same cell of the tables is compared with it
+// cmd/evaluate reads a csv or tsv file of attested forms and prints how many are right, by conjugation, variant and tense
+
+package bescherelle
+
+import (
+	"conjugator/converter"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type AttestedForm struct { // one line of a file of attested forms
+	Line        int    // the line of the file it is on, for the report
+	Verb        string // the verb as it would be typed on the page, in Orthography
+	Tense       string // the title of the table, e.g. "Past Attestive", in any language of the page
+	Person      string // the person, e.g. "He/She/They", or the subject and object for transitive verbs, e.g. "I > You"
+	Form        string // the form that is attested, in Orthography
+	Orthography string // any of converter.Orthographies, francissmith if it is left empty
+}
+
+type EvaluatedForm struct { // an attested form, and what the conjugator gives for it
+	AttestedForm
+	Conjugation string // the conjugation of the verb as the page shows it, e.g. "1~3"
+	Variant     string // the variant of the verb in conjdict.json, with its model, e.g. "ink (pekisink)"
+	Table       string // the english title of the table, e.g. "Past Attestive"
+	Conjugated  string // the form in the same cell of the tables, in Orthography, with its variants separated by commas
+	Correct     bool   // if the attested form is the conjugated form, or one of its variants
+	Skipped     bool   // if the line cannot be compared with the tables (e.g. a tense the verb does not have), so it is not counted
+	Problem     string // why there is no conjugated form, if there is none
+}
+
+var ErrAttestedFormColumns = errors.New("a line of attested forms has to have the verb, tense, person, form and orthography")
+
+// the languages of the page, whose table titles and persons can be used in files of attested forms
+var evaluationLanguages = []string{"ENGL", "FREN", "MKMW"}
+
+// ReadAttestedForms reads attested forms from csv (separator ',') or tsv (separator '\t'),
+// one on each line, with the columns verb, tense, person, form and orthography (which can be left out)
+// lines starting with # and a first line of column names starting with "verb" or "lemma" are left out
+func ReadAttestedForms(reader io.Reader, separator rune) ([]AttestedForm, error) {
+	var AttestedForms []AttestedForm
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = separator
+	csvReader.Comment = '#'
+	csvReader.FieldsPerRecord = -1 // the orthography can be left out
+	csvReader.LazyQuotes = true    // quotes are not special in the middle of a field
+	for {
+		record, readErr := csvReader.Read()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+		line, _ := csvReader.FieldPos(0)
+		for fieldIndex := range record {
+			record[fieldIndex] = strings.TrimSpace(record[fieldIndex])
+		}
+		if len(AttestedForms) == 0 && (strings.EqualFold(record[0], "verb") || strings.EqualFold(record[0], "lemma")) { // the names of the columns
+			continue
+		}
+		if len(record) == 1 && record[0] == "" { // an empty line of a spreadsheet
+			continue
+		}
+		if len(record) < 4 || len(record) > 5 {
+			return nil, fmt.Errorf("line %d: %w", line, ErrAttestedFormColumns)
+		}
+		ThisForm := AttestedForm{Line: line, Verb: record[0], Tense: record[1], Person: record[2], Form: record[3], Orthography: "francissmith"}
+		if len(record) == 5 && record[4] != "" {
+			ThisForm.Orthography = strings.ToLower(record[4])
+		}
+		AttestedForms = append(AttestedForms, ThisForm)
+	}
+	return AttestedForms, nil
+}
+
+type evaluationTables struct { // the tables of one verb in one orthography, in every language of the page
+	InputVerb Verb
+	Tables    map[string]Data // by language
+	Err       error
+}
+
+// EvaluateAttestedForms conjugates the verb of every attested form and compares the form with the conjugated one
+// the dictionaries have to be loaded first (see LoadDictionaries)
+func EvaluateAttestedForms(AttestedForms []AttestedForm) []EvaluatedForm {
+	var EvaluatedForms []EvaluatedForm
+	conjugatedVerbs := make(map[string]evaluationTables) // a verb is only conjugated once for each orthography
+	for _, ThisForm := range AttestedForms {
+		Evaluated := EvaluatedForm{AttestedForm: ThisForm}
+		if _, found := converter.OrthographyNames[ThisForm.Orthography]; !found {
+			Evaluated.Skipped = true
+			Evaluated.Problem = fmt.Sprintf("%v: %s", converter.ErrUnknownOrthography, ThisForm.Orthography)
+			EvaluatedForms = append(EvaluatedForms, Evaluated)
+			continue
+		}
+		verbKey := ThisForm.Orthography + " " + strings.ToLower(ThisForm.Verb)
+		Conjugated, found := conjugatedVerbs[verbKey]
+		if !found {
+			Conjugated = conjugateForEvaluation(ThisForm.Verb, ThisForm.Orthography)
+			conjugatedVerbs[verbKey] = Conjugated
+		}
+		if Conjugated.Err != nil { // counted as wrong: the conjugator should know the verb
+			Evaluated.Problem = Conjugated.Err.Error()
+			EvaluatedForms = append(EvaluatedForms, Evaluated)
+			continue
+		}
+		conjugation, model, _ := localizeOutput("ENGL", Conjugated.InputVerb)
+		Evaluated.Conjugation = conjugation
+		Evaluated.Variant = model
+		if Conjugated.InputVerb.ConjugationVariant != "" {
+			Evaluated.Variant = fmt.Sprintf("%s (%s)", Conjugated.InputVerb.ConjugationVariant, model)
+		}
+		tableIndex, rowIndex, columnIndex, findErr := findCell(Conjugated.Tables, ThisForm.Tense, ThisForm.Person)
+		if findErr != nil {
+			Evaluated.Skipped = true
+			Evaluated.Problem = findErr.Error()
+			EvaluatedForms = append(EvaluatedForms, Evaluated)
+			continue
+		}
+		EnglishTable := Conjugated.Tables["ENGL"].Tables[tableIndex]
+		Evaluated.Table = EnglishTable.Title
+		Evaluated.Conjugated = EnglishTable.RowsAndColumns[rowIndex][columnIndex]
+		if Evaluated.Conjugated == "" || Evaluated.Conjugated == "*" {
+			Evaluated.Conjugated = ""
+			Evaluated.Problem = "the conjugator has no form for this person"
+		}
+		attestedForm := normalizeEvaluatedForm(ThisForm.Form, ThisForm.Orthography)
+		for _, variant := range strings.Split(Evaluated.Conjugated, ",") {
+			if variant != "" && normalizeEvaluatedForm(variant, ThisForm.Orthography) == attestedForm {
+				Evaluated.Correct = true
+			}
+		}
+		EvaluatedForms = append(EvaluatedForms, Evaluated)
+	}
+	return EvaluatedForms
+}
+
+// conjugates a verb the way the page does in an orthography, and makes its tables in every language of the page
+func conjugateForEvaluation(verb string, orthographyChoice string) evaluationTables {
+	var Conjugated evaluationTables
+	Conjugated.Err = converter.ValidateWord(verb, orthographyChoice)
+	if Conjugated.Err != nil {
+		return Conjugated
+	}
+	var ConjugationArray [][]string
+	FrancisSmithStr := convertForm(strings.ToLower(strings.TrimSpace(verb)), orthographyChoice, "francissmith", false)
+	ConjugationArray, Conjugated.InputVerb, Conjugated.Err = readoutVerb(FrancisSmithStr)
+	if Conjugated.Err != nil {
+		return Conjugated
+	}
+	ConjugationArray = convertConjugationArray(ConjugationArray, orthographyChoice, false)
+	Conjugated.Tables = make(map[string]Data)
+	for _, languageChoice := range evaluationLanguages {
+		Conjugated.Tables[languageChoice] = makeTables(ConjugationArray, Conjugated.InputVerb, languageChoice)
+	}
+	return Conjugated
+}
+
+// finds the table, row and column of a tense and person in the tables of a verb, in whichever language of the page they are written in
+// the tables are in the same order in every language, so the english table is found at the same place
+func findCell(Tables map[string]Data, tense string, person string) (int, int, int, error) {
+	subject, object, transitive := strings.Cut(person, ">")
+	subject, object = strings.TrimSpace(subject), strings.TrimSpace(object)
+	foundTense := false
+	for _, languageChoice := range evaluationLanguages {
+		for tableIndex, ThisTable := range Tables[languageChoice].Tables {
+			if !strings.EqualFold(ThisTable.Title, strings.TrimSpace(tense)) {
+				continue
+			}
+			foundTense = true
+			hasObjects := ThisTable.Type == VTI || ThisTable.Type == VTA
+			if hasObjects != transitive { // the person of a transitive verb has to have an object, and an intransitive one cannot
+				continue
+			}
+			firstRow, columnIndex := 0, 1
+			if hasObjects { // the first row of transitive tables is the object header
+				firstRow, columnIndex = 1, -1
+				for headerIndex, header := range ThisTable.RowsAndColumns[0] {
+					if headerIndex > 0 && strings.EqualFold(header, object) {
+						columnIndex = headerIndex
+					}
+				}
+				if columnIndex < 0 {
+					continue
+				}
+			}
+			for rowIndex := firstRow; rowIndex < len(ThisTable.RowsAndColumns); rowIndex++ {
+				if strings.EqualFold(ThisTable.RowsAndColumns[rowIndex][0], subject) {
+					return tableIndex, rowIndex, columnIndex, nil
+				}
+			}
+		}
+	}
+	if !foundTense {
+		return 0, 0, 0, fmt.Errorf("the verb has no table %q", tense)
+	}
+	return 0, 0, 0, fmt.Errorf("the table %q has no person %q", tense, person)
+}
+
+// writes a form the same way however it was typed, so that it can be compared with another
+func normalizeEvaluatedForm(form string, orthographyChoice string) string {
+	form = converter.Substitute(converter.NormalizeUnicode(strings.ToLower(form)), orthographyChoice)
+	return strings.Join(strings.Fields(form), " ")
+}
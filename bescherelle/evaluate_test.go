@@ -0,0 +1,64 @@
+// tests for evaluating the conjugator against attested forms, with the lines of testdata/attested.tsv
+
+package bescherelle
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// every line of testdata/attested.tsv is read, compared with the tables, and counted or not counted as it should be
+func TestEvaluateAttestedForms(t *testing.T) {
+	loadConjugationDictionary(t)
+	loadLocalizationDictionary(t)
+	attestedFile, openErr := os.Open("testdata/attested.tsv")
+	if openErr != nil {
+		t.Fatal(openErr)
+	}
+	defer attestedFile.Close()
+	AttestedForms, readErr := ReadAttestedForms(attestedFile, '\t')
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	expected := []struct {
+		line       int
+		conjugated string
+		correct    bool
+		skipped    bool
+	}{
+		{3, "teluisi", true, false},
+		{4, "teluisi'p", true, false}, // the tense and person in french, and the orthography left out
+		{5, "teluisip", false, false}, // a form the conjugator does not give
+		{6, "gesalul", true, false},   // a transitive verb in listuguj
+		{7, "", false, false},         // a person the verb has no form for
+		{8, "", false, true},          // a tense the verb does not have
+		{9, "", false, false},         // a verb the conjugator does not recognize
+		{10, "", false, true},         // an orthography that does not exist
+	}
+	EvaluatedForms := EvaluateAttestedForms(AttestedForms)
+	if len(EvaluatedForms) != len(expected) {
+		t.Fatalf("%d attested forms were evaluated, not %d", len(EvaluatedForms), len(expected))
+	}
+	for formIndex, Evaluated := range EvaluatedForms {
+		want := expected[formIndex]
+		if Evaluated.Line != want.line || Evaluated.Conjugated != want.conjugated || Evaluated.Correct != want.correct || Evaluated.Skipped != want.skipped {
+			t.Errorf("line %d (%s, %s, %s): got line %d, %q, correct %v, skipped %v (%s); want %q, correct %v, skipped %v",
+				want.line, Evaluated.Verb, Evaluated.Tense, Evaluated.Person, Evaluated.Line, Evaluated.Conjugated, Evaluated.Correct,
+				Evaluated.Skipped, Evaluated.Problem, want.conjugated, want.correct, want.skipped)
+		}
+	}
+	if EvaluatedForms[0].Conjugation != "1" || EvaluatedForms[0].Variant != "std (teluisit)" || EvaluatedForms[1].Table != "Past Attestive" {
+		t.Errorf("teluisit is in conjugation %q, variant %q, and its table is %q", EvaluatedForms[0].Conjugation, EvaluatedForms[0].Variant, EvaluatedForms[1].Table)
+	}
+}
+
+// a line without the form is an error, with the line it is on
+func TestReadAttestedFormsColumns(t *testing.T) {
+	_, readErr := ReadAttestedForms(strings.NewReader("teluisit,Present,I,teluisi\nteluisit,Present\n"), ',')
+	if !errors.Is(readErr, ErrAttestedFormColumns) || !strings.Contains(readErr.Error(), "line 2") {
+		t.Errorf("got %v, want line 2: %v", readErr, ErrAttestedFormColumns)
+	}
+}
@@ -0,0 +1,134 @@
+// fuzz tests for reading verbs: parseVerb, contractStem, and conjugating whatever they return
+// the seeds are the model verbs of every conjugation, so the fuzzer starts from every branch of parseVerb
+// go test runs the seeds; go test -fuzz=FuzzParseVerb ./bescherelle runs the fuzzer
+
+package bescherelle
+
+import (
+	"conjugator/converter"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+// the same verbs typed in other ways, and input that is not a verb at all
+var oddVerbs = []string{"", "k", "e", "at", "et", "it", "aq", "tk", "ɨk", "tɨk", "atl", "a'tl", "iet", "Teluisit", "TELUISIT", "telu*sit", "ewi’kiket", "ewiʼkiket", "nest*k", "ôpla", "ăt", "a'sɨk"}
+
+var loadDictionary sync.Once
+
+// reads the conjugation dictionary the way LoadDictionaries does, from the folder the tests run in
+func loadConjugationDictionary(t testing.TB) {
+	loadDictionary.Do(func() {
+		dictionaryBytes, readErr := os.ReadFile("conjdict.json")
+		if readErr != nil {
+			t.Fatal(readErr)
+		}
+		unmarshalErr := json.Unmarshal(dictionaryBytes, &ConjugationDictionary)
+		if unmarshalErr != nil {
+			t.Fatal(unmarshalErr)
+		}
+	})
+}
+
+func addVerbSeeds(f *testing.F) {
+	for _, verb := range append(append([]string{}, ModelVerbs...), oddVerbs...) {
+		f.Add(verb)
+	}
+}
+
+// a verb that is nothing but an ending has no stem to conjugate, so it is not recognized
+func TestParseVerbOnlyAnEnding(t *testing.T) {
+	for _, InputStr := range []string{"at", "et", "it", "aq", "tk", "ɨk", "atl", "a'tl", "iet"} {
+		InputVerb, parseErr := parseVerb(InputStr)
+		if !errors.Is(parseErr, ErrUnknownEnding) {
+			t.Errorf("parseVerb(%q) = %+v, %v, but it should be %v", InputStr, InputVerb, parseErr, ErrUnknownEnding)
+		}
+	}
+}
+
+// parseVerb never panics, recognizes every model verb, gives every verb it recognizes a stem, and reads the same verb however it was typed
+func FuzzParseVerb(f *testing.F) {
+	addVerbSeeds(f)
+	f.Fuzz(func(t *testing.T, InputStr string) {
+		InputVerb, parseErr := parseVerb(InputStr)
+		if parseErr != nil {
+			for _, verb := range ModelVerbs {
+				if InputStr == verb {
+					t.Errorf("the model verb %q is not recognized: %v", verb, parseErr)
+				}
+			}
+			return
+		}
+		if InputVerb.Conjugation < 1 || InputVerb.Conjugation > 7 {
+			t.Errorf("parseVerb(%q) gave conjugation %d", InputStr, InputVerb.Conjugation)
+		}
+		if InputVerb.Stem == "" {
+			t.Errorf("parseVerb(%q) gave no stem", InputStr)
+		}
+		if utf8.ValidString(InputStr) && !utf8.ValidString(InputVerb.Stem) {
+			t.Errorf("parseVerb(%q) gave a stem that is not utf-8: %q", InputStr, InputVerb.Stem)
+		}
+		if utf8.RuneCountInString(InputVerb.Stem) > utf8.RuneCountInString(InputStr) {
+			t.Errorf("parseVerb(%q) gave a stem longer than the verb: %q", InputStr, InputVerb.Stem)
+		}
+		NormalizedVerb, normalizedErr := parseVerb(converter.NormalizeUnicode(InputStr))
+		if normalizedErr != nil || NormalizedVerb != InputVerb {
+			t.Errorf("parseVerb(%q) = %+v, but the same verb normalized gives %+v, %v", InputStr, InputVerb, NormalizedVerb, normalizedErr)
+		}
+	})
+}
+
+// contractStem never panics, keeps text utf-8, and adds at most two characters (i' for y, and a schwa)
+func FuzzContractStem(f *testing.F) {
+	for _, verb := range ModelVerbs {
+		InputVerb, _ := parseVerb(verb)
+		f.Add(InputVerb.Stem, InputVerb.Conjugation)
+	}
+	for _, stem := range []string{"", "e", "y", "ey", "ne", "nen", "eypk", "tepk", "ăep", "ɨek", "kɨ"} {
+		for conjugation := 1; conjugation <= 7; conjugation++ {
+			f.Add(stem, conjugation)
+		}
+	}
+	f.Fuzz(func(t *testing.T, Stem string, Conjugation int) {
+		ContractedStem := contractStem(Stem, Conjugation)
+		if utf8.ValidString(Stem) && !utf8.ValidString(ContractedStem) {
+			t.Errorf("contractStem(%q, %d) = %q, which is not utf-8", Stem, Conjugation, ContractedStem)
+		}
+		if utf8.RuneCountInString(ContractedStem) > utf8.RuneCountInString(Stem)+2 {
+			t.Errorf("contractStem(%q, %d) = %q, which is too long", Stem, Conjugation, ContractedStem)
+		}
+	})
+}
+
+// every verb parseVerb reads can be conjugated without panicking, and has forms if it is a model verb
+func FuzzConjugateVerb(f *testing.F) {
+	loadConjugationDictionary(f)
+	addVerbSeeds(f)
+	f.Fuzz(func(t *testing.T, InputStr string) {
+		ConjugationArray, _, readoutErr := readoutVerb(InputStr)
+		if readoutErr != nil {
+			return
+		}
+		for _, verb := range ModelVerbs {
+			if InputStr == verb && len(ConjugationArray) == 0 {
+				t.Errorf("the model verb %q has no forms", verb)
+			}
+		}
+		for _, tense := range ConjugationArray {
+			for _, form := range tense {
+				if utf8.ValidString(InputStr) && !utf8.ValidString(form) {
+					t.Errorf("%q has a form that is not utf-8: %q", InputStr, form)
+				}
+				for _, word := range strings.Fields(form) { // a * on its own marks a form that does not exist
+					if word != "*" && strings.Contains(word, "*") {
+						t.Errorf("%q has a form with a schwa that was not written back as ɨ: %q", InputStr, form)
+					}
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,194 @@
+// golden tests for the paradigms of the model verbs: every table of every model verb is checked against testdata/paradigms,
+// so that a change to conjdict.json or to conjugateVerb that changes a form is seen before it reaches the page
+// the files are the json output of the conjugator (?format=json) in francis-smith, with the tables in english
+// after a change that is meant to change forms, the files are written again with:
+//
+//	go test ./bescherelle -run TestParadigms -update
+//
+// and the diff of testdata/paradigms shows every form that changed
+
+package bescherelle
+
+import (
+	"bytes"
+	"conjugator/converter"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var updateParadigms = flag.Bool("update", false, "write the paradigms in testdata/paradigms again instead of checking them")
+
+var loadLocalization sync.Once
+
+// reads the localization dictionary the way LoadDictionaries does, for the titles of the tables
+func loadLocalizationDictionary(t testing.TB) {
+	loadLocalization.Do(func() {
+		localizationBytes, readErr := os.ReadFile("localization.json")
+		if readErr != nil {
+			t.Fatal(readErr)
+		}
+		unmarshalErr := json.Unmarshal(localizationBytes, &LocalizationDictionary)
+		if unmarshalErr != nil {
+			t.Fatal(unmarshalErr)
+		}
+	})
+}
+
+// the file a model verb's paradigm is kept in
+func paradigmPath(verb string) string {
+	return filepath.Join("testdata", "paradigms", verb+".json")
+}
+
+// conjugates a verb the way the page does in francis-smith and english, and returns its json output
+func renderParadigm(t *testing.T, verb string) []byte {
+	var page MainPage
+	ConjugationArray, InputVerb, readoutErr := readoutVerb(verb)
+	if readoutErr != nil {
+		t.Fatalf("the model verb %q cannot be conjugated: %v", verb, readoutErr)
+	}
+	page.TableData = makeTables(ConjugationArray, InputVerb, "ENGL")
+	page.OutputConjugation, page.OutputModel, page.Disclaimer = localizeOutput("ENGL", InputVerb)
+	page.InputString = verb
+	page.Orthography = "francissmith"
+	paradigmBytes, marshalErr := json.MarshalIndent(makeJSONOutput(page), "", "\t")
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+	return append(paradigmBytes, '\n')
+}
+
+// turns a paradigm into one line for each form ("Present | I | | teluisi"), so that a mismatch can be shown form by form
+func paradigmLines(t *testing.T, paradigmBytes []byte) (map[string]string, []string) {
+	var Paradigm JSONOutput
+	unmarshalErr := json.Unmarshal(paradigmBytes, &Paradigm)
+	if unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+	forms := map[string]string{
+		"conjugation": Paradigm.Conjugation,
+		"model":       Paradigm.Model,
+		"disclaimer":  Paradigm.Disclaimer,
+	}
+	order := []string{"conjugation", "model", "disclaimer"}
+	for tableIndex, table := range Paradigm.Tables {
+		for _, row := range table.Rows {
+			for formIndex, form := range row.Forms {
+				object := ""
+				if formIndex < len(table.Header) {
+					object = table.Header[formIndex]
+				}
+				key := fmt.Sprintf("%d %s | %s | %s", tableIndex+1, table.Title, row.Person, object)
+				if _, found := forms[key]; !found {
+					order = append(order, key)
+				}
+				forms[key] = form["francissmith"]
+			}
+		}
+	}
+	return forms, order
+}
+
+// describes every form that is not the same in the two paradigms, in the order of the tables
+func diffParadigms(t *testing.T, wantBytes []byte, gotBytes []byte) string {
+	wantForms, wantOrder := paradigmLines(t, wantBytes)
+	gotForms, gotOrder := paradigmLines(t, gotBytes)
+	var diff strings.Builder
+	for _, key := range wantOrder {
+		gotForm, found := gotForms[key]
+		switch {
+		case !found:
+			fmt.Fprintf(&diff, "\t- %s: %q (no longer made)\n", key, wantForms[key])
+		case gotForm != wantForms[key]:
+			fmt.Fprintf(&diff, "\t  %s: %q, was %q\n", key, gotForm, wantForms[key])
+		}
+	}
+	for _, key := range gotOrder {
+		if _, found := wantForms[key]; !found {
+			fmt.Fprintf(&diff, "\t+ %s: %q (new)\n", key, gotForms[key])
+		}
+	}
+	if diff.Len() == 0 { // the forms are the same, so something else in the file is different, e.g. how it is indented
+		return "\tthe forms are the same, but the file is not what the conjugator writes\n"
+	}
+	return diff.String()
+}
+
+// every model verb is conjugated the same as in its file in testdata/paradigms
+func TestParadigms(t *testing.T) {
+	loadConjugationDictionary(t)
+	loadLocalizationDictionary(t)
+	if *updateParadigms {
+		makeErr := os.MkdirAll(filepath.Join("testdata", "paradigms"), 0755)
+		if makeErr != nil {
+			t.Fatal(makeErr)
+		}
+	}
+	for _, verb := range ModelVerbs {
+		t.Run(verb, func(t *testing.T) {
+			gotBytes := renderParadigm(t, verb)
+			if *updateParadigms {
+				writeErr := os.WriteFile(paradigmPath(verb), gotBytes, 0644)
+				if writeErr != nil {
+					t.Fatal(writeErr)
+				}
+				return
+			}
+			wantBytes, readErr := os.ReadFile(paradigmPath(verb))
+			if readErr != nil {
+				t.Fatalf("%v (run go test ./bescherelle -run TestParadigms -update to write it)", readErr)
+			}
+			if !bytes.Equal(wantBytes, gotBytes) {
+				t.Errorf("the paradigm of %q has changed:\n%sif this is meant to be, run go test ./bescherelle -run TestParadigms -update",
+					verb, diffParadigms(t, wantBytes, gotBytes))
+			}
+		})
+	}
+}
+
+// the error of the json output is plain text: the input comes out as it was typed, and the html of the page is left out
+func TestJSONErrorIsPlainText(t *testing.T) {
+	loadLocalizationDictionary(t)
+	var page MainPage
+	page.Error, page.ErrorCode = localizeError("ENGL", converter.ValidateWord("<b>", "francissmith"))
+	if !strings.Contains(string(page.Error), "&lt;") {
+		t.Errorf("the error on the page does not escape the input: %s", page.Error)
+	}
+	Output := makeJSONOutput(page)
+	want := fmt.Sprintf(LocalizationDictionary["ENGL"].ErrorInvalidCharacter, "<", converter.OrthographyNames["francissmith"])
+	want = strings.NewReplacer("<i>", "", "</i>", "").Replace(want)
+	if Output.Error != want || Output.Code != "invalid_character" {
+		t.Errorf("the json error is %q (%s), want %q (invalid_character)", Output.Error, Output.Code, want)
+	}
+}
+
+// every mi'kmaw string that is still the same as in english is marked as needing translation (except the sources, which are cited in english)
+func TestUntranslatedStringsAreMarked(t *testing.T) {
+	loadLocalizationDictionary(t)
+	localizationBytes, readErr := os.ReadFile("localization.json")
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	var Strings map[string]map[string]json.RawMessage
+	unmarshalErr := json.Unmarshal(localizationBytes, &Strings)
+	if unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+	marked := make(map[string]bool)
+	for _, key := range LocalizationDictionary["MKMW"].NeedsTranslation {
+		if _, found := Strings["MKMW"][key]; !found {
+			t.Errorf("%q is marked as needing translation, but there is no such string", key)
+		}
+		marked[key] = true
+	}
+	for key, value := range Strings["MKMW"] {
+		if key != "sourcefield" && bytes.Equal(value, Strings["ENGL"][key]) && !marked[key] {
+			t.Errorf("the mi'kmaw %q is the same as the english, but is not in needstranslation", key)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// converts a document between orthographies from the command line, in the same way as the file upload on the OrthoConverter page
+// only the readable text is converted: tags, code, subtitle timestamps, and the formatting of word processor documents are left as they are
+//
+//	go run ./cmd/convertfile -from listuguj -to francissmith story.md > story-francissmith.md
+//	go run ./cmd/convertfile -to listuguj -o video-listuguj.srt video.srt
+//	go run ./cmd/convertfile -from francissmith -to listuguj -o lesson-listuguj.docx lesson.docx
+//	cat notes.txt | go run ./cmd/convertfile -from auto -to metallic
+//
+// the format is read from the file extension (.txt, .md, .html, .srt, .vtt, .docx or .odt), or given with -format when reading from standard input
+// orthography files in converter/orthographies are read if the command is run from the root of the repository
+
+package main
+
+import (
+	"conjugator/converter"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	fromOrthography := flag.String("from", "auto", "the orthography the document is written in, or auto to detect it")
+	toOrthography := flag.String("to", "francissmith", "the orthography to convert the document to")
+	format := flag.String("format", "", "the format of the document (txt, md, html, srt, vtt, docx or odt), if it cannot be read from the file name")
+	outputPath := flag.String("o", "", "the file to write the converted document to, instead of standard output")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: convertfile [-from orthography] [-to orthography] [-format format] [-o output] [file]")
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output(), "orthographies:", converter.Orthographies)
+	}
+	flag.Parse()
+
+	loadErr := converter.LoadOrthographies()
+	if loadErr != nil {
+		fmt.Fprintln(os.Stderr, loadErr)
+		os.Exit(1)
+	}
+
+	var contents []byte
+	var readErr error
+	if flag.NArg() > 0 {
+		contents, readErr = os.ReadFile(flag.Arg(0))
+		if readErr == nil && *format == "" {
+			*format, readErr = converter.DocumentFormat(flag.Arg(0))
+		}
+	} else {
+		contents, readErr = io.ReadAll(os.Stdin)
+		if *format == "" {
+			*format = "txt"
+		}
+	}
+	if readErr != nil {
+		fmt.Fprintln(os.Stderr, readErr)
+		os.Exit(1)
+	}
+
+	convertedFile, convertErr := converter.ConvertFile(contents, *format, *fromOrthography, *toOrthography)
+	if convertErr != nil {
+		fmt.Fprintln(os.Stderr, convertErr)
+		os.Exit(1)
+	}
+	if *outputPath == "" {
+		os.Stdout.Write(convertedFile)
+		return
+	}
+	writeErr := os.WriteFile(*outputPath, convertedFile, 0644)
+	if writeErr != nil {
+		fmt.Fprintln(os.Stderr, writeErr)
+		os.Exit(1)
+	}
+}
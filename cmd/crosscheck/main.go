@@ -0,0 +1,82 @@
+// checks the OrthoConverter against the converters bescherelle had of its own, on every form of the model verbs, in listuguj and metallic
+// each form is converted from francis-smith into the orthography and read back, with both, and every form they do not agree on,
+// or that is not read back as it was, is printed as a line of the table, so that which of them is wrong can be decided
+//
+//	go run ./cmd/crosscheck
+//	go run ./cmd/crosscheck -verbs teluisit,kesalatl -to metallic
+//
+// forms that both read back the same, and that only differ from what they were in what the orthography writes like something else
+// (see converter.Orthography.Losses), e.g. y written i in listuguj, are known losses: they are counted after the table, and listed with -known
+//
+// the exit status is 1 if anything other than a known loss disagrees
+// it has to be run from the root of the repository, where the dictionaries and orthography files are
+
+package main
+
+import (
+	"conjugator/bescherelle"
+	"conjugator/converter"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func main() {
+	verbList := flag.String("verbs", strings.Join(bescherelle.ModelVerbs, ","), "the verbs to conjugate, separated by commas")
+	orthographyList := flag.String("to", strings.Join(bescherelle.CrossCheckOrthographies, ","), "the orthographies to convert the forms to, separated by commas")
+	listKnown := flag.Bool("known", false, "also list the known losses, forms that cannot be read back because the orthography writes something in them like something else")
+	flag.Parse()
+
+	loadErr := converter.LoadOrthographies()
+	if loadErr == nil {
+		loadErr = bescherelle.LoadDictionaries()
+	}
+	if loadErr != nil {
+		fmt.Fprintln(os.Stderr, loadErr)
+		os.Exit(1)
+	}
+
+	Disagreements, checkErr := bescherelle.CrossCheck(strings.Split(*verbList, ","), strings.Split(*orthographyList, ","))
+	if checkErr != nil {
+		fmt.Fprintln(os.Stderr, checkErr)
+		os.Exit(1)
+	}
+	var Unexpected, Known []bescherelle.Disagreement
+	for _, ThisDisagreement := range Disagreements {
+		if ThisDisagreement.Known {
+			Known = append(Known, ThisDisagreement)
+		} else {
+			Unexpected = append(Unexpected, ThisDisagreement)
+		}
+	}
+
+	if len(Unexpected) == 0 {
+		fmt.Println("bescherelle and the converter agree on every form")
+	} else {
+		printDisagreements(Unexpected)
+		fmt.Println(len(Unexpected), "disagreements")
+	}
+	if len(Known) > 0 {
+		if *listKnown {
+			fmt.Println()
+			printDisagreements(Known)
+		}
+		fmt.Println(len(Known), "known losses: forms the orthography writes like other forms, which both read back the same (listed with -known)")
+	}
+	if len(Unexpected) > 0 {
+		os.Exit(1)
+	}
+}
+
+// prints disagreements as a table, one form a line
+func printDisagreements(Disagreements []bescherelle.Disagreement) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "verb\torthography\tdirection\tform\texpected\tbescherelle\tconverter")
+	for _, ThisDisagreement := range Disagreements {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", ThisDisagreement.Verb, ThisDisagreement.Orthography, ThisDisagreement.Direction,
+			ThisDisagreement.Input, ThisDisagreement.Expected, ThisDisagreement.Bescherelle, ThisDisagreement.Converter)
+	}
+	writer.Flush()
+}
@@ -0,0 +1,190 @@
+// measures how many attested forms the conjugator gets right, from a csv or tsv file of attested forms
+// every line is a verb, a tense, a person, the attested form, and the orthography they are written in, e.g.
+//
+//	lemma	tense	person	form	orthography
+//	teluisit	Past Attestive	I	teluisip	francissmith
+//	kesalatl	Present	I > You	kesalul	listuguj
+//
+// the tense and person are the titles of the tables and the persons as the page shows them, in any of its languages;
+// for transitive verbs the person is the subject and the object, separated by >
+// the report gives the accuracy by conjugation, variant and tense, the worst first, and lists every form the conjugator gets wrong
+//
+//	go run ./cmd/evaluate attested.tsv
+//	go run ./cmd/evaluate -mismatches=false grammar.csv
+//
+// the separator is read from the file extension (.csv or .tsv), or from the first line if there is a tab in it
+// it has to be run from the root of the repository, where the dictionaries and orthography files are
+
+package main
+
+import (
+	"bytes"
+	"conjugator/bescherelle"
+	"conjugator/converter"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type accuracy struct { // how many forms of a conjugation, variant or tense are right
+	Name    string
+	Correct int
+	Total   int
+}
+
+func main() {
+	showMismatches := flag.Bool("mismatches", true, "list every form the conjugator gets wrong")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: evaluate [-mismatches=false] [file]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var contents []byte
+	var readErr error
+	if flag.NArg() > 0 {
+		contents, readErr = os.ReadFile(flag.Arg(0))
+	} else {
+		contents, readErr = io.ReadAll(os.Stdin)
+	}
+	if readErr != nil {
+		fmt.Fprintln(os.Stderr, readErr)
+		os.Exit(1)
+	}
+
+	separator := ','
+	firstLine, _, _ := bytes.Cut(contents, []byte("\n"))
+	switch strings.ToLower(filepath.Ext(flag.Arg(0))) {
+	case ".tsv", ".tab":
+		separator = '\t'
+	case ".csv":
+	default:
+		if bytes.ContainsRune(firstLine, '\t') {
+			separator = '\t'
+		}
+	}
+	AttestedForms, readErr := bescherelle.ReadAttestedForms(bytes.NewReader(contents), separator)
+	if readErr != nil {
+		fmt.Fprintln(os.Stderr, readErr)
+		os.Exit(1)
+	}
+
+	loadErr := converter.LoadOrthographies()
+	if loadErr == nil {
+		loadErr = bescherelle.LoadDictionaries()
+	}
+	if loadErr != nil {
+		fmt.Fprintln(os.Stderr, loadErr)
+		os.Exit(1)
+	}
+
+	EvaluatedForms := bescherelle.EvaluateAttestedForms(AttestedForms)
+	var counted []bescherelle.EvaluatedForm
+	var skipped []bescherelle.EvaluatedForm
+	for _, Evaluated := range EvaluatedForms {
+		if Evaluated.Skipped {
+			skipped = append(skipped, Evaluated)
+		} else {
+			counted = append(counted, Evaluated)
+		}
+	}
+
+	Overall := countCorrect(counted, func(bescherelle.EvaluatedForm) string { return "all" })
+	if len(Overall) == 0 {
+		fmt.Println("there are no attested forms to compare")
+	} else {
+		fmt.Printf("accuracy: %s\n", percentage(Overall[0]))
+	}
+	printAccuracy("conjugation", countCorrect(counted, func(Evaluated bescherelle.EvaluatedForm) string { return recognized(Evaluated.Conjugation) }))
+	printAccuracy("variant", countCorrect(counted, func(Evaluated bescherelle.EvaluatedForm) string { return recognized(Evaluated.Variant) }))
+	printAccuracy("tense", countCorrect(counted, func(Evaluated bescherelle.EvaluatedForm) string {
+		if Evaluated.Table == "" { // the verb was not recognized, so the tense is as it was written
+			return Evaluated.Tense
+		}
+		return Evaluated.Table
+	}))
+
+	if *showMismatches {
+		fmt.Println()
+		fmt.Println("mismatches")
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "line\tverb\ttense\tperson\torthography\tattested\tconjugator")
+		for _, Evaluated := range counted {
+			if Evaluated.Correct {
+				continue
+			}
+			conjugated := Evaluated.Conjugated
+			if Evaluated.Problem != "" {
+				conjugated = fmt.Sprintf("(%s)", Evaluated.Problem)
+			}
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", Evaluated.Line, Evaluated.Verb, Evaluated.Tense, Evaluated.Person,
+				Evaluated.Orthography, Evaluated.Form, conjugated)
+		}
+		writer.Flush()
+	}
+
+	if len(skipped) > 0 {
+		fmt.Println()
+		fmt.Println("not counted")
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, Evaluated := range skipped {
+			fmt.Fprintf(writer, "%d\t%s\t%s\n", Evaluated.Line, Evaluated.Verb, Evaluated.Problem)
+		}
+		writer.Flush()
+	}
+}
+
+// counts the forms that are right for every name the forms are grouped by, the worst first
+func countCorrect(EvaluatedForms []bescherelle.EvaluatedForm, groupName func(bescherelle.EvaluatedForm) string) []accuracy {
+	groups := make(map[string]*accuracy)
+	var Accuracies []accuracy
+	for _, Evaluated := range EvaluatedForms {
+		name := groupName(Evaluated)
+		if groups[name] == nil {
+			groups[name] = &accuracy{Name: name}
+		}
+		groups[name].Total++
+		if Evaluated.Correct {
+			groups[name].Correct++
+		}
+	}
+	for _, Group := range groups {
+		Accuracies = append(Accuracies, *Group)
+	}
+	sort.Slice(Accuracies, func(first, second int) bool {
+		firstShare := float64(Accuracies[first].Correct) / float64(Accuracies[first].Total)
+		secondShare := float64(Accuracies[second].Correct) / float64(Accuracies[second].Total)
+		if firstShare != secondShare {
+			return firstShare < secondShare
+		}
+		return Accuracies[first].Name < Accuracies[second].Name
+	})
+	return Accuracies
+}
+
+// the conjugation or variant of a verb, or that it was not recognized
+func recognized(name string) string {
+	if name == "" {
+		return "(not recognized)"
+	}
+	return name
+}
+
+func percentage(Group accuracy) string {
+	return fmt.Sprintf("%d/%d (%.1f%%)", Group.Correct, Group.Total, 100*float64(Group.Correct)/float64(Group.Total))
+}
+
+func printAccuracy(title string, Accuracies []accuracy) {
+	fmt.Println()
+	fmt.Println("by", title)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, Group := range Accuracies {
+		fmt.Fprintf(writer, "%s\t%s\n", Group.Name, percentage(Group))
+	}
+	writer.Flush()
+}
@@ -0,0 +1,118 @@
+// possible readings of words in orthographies that under-specify sounds
+// pacifique and rand do not always write vowel length, and the converter can only guess at the uvular fricative,
+// so instead of a single guess, every word gets a ranked list of francis-smith readings
+// readings that are in the wordlist come first, then readings that differ least from the converter's first guess
+
+package converter
+
+import (
+	"sort"
+	"strings"
+)
+
+type Candidate struct { // a possible reading of a word
+	Unified      string `json:"unified"`
+	FrancisSmith string `json:"francissmith"`
+	Changes      int    `json:"changes"` // how many characters are read differently from the first guess
+	Known        bool   `json:"known"`   // if the reading is in the wordlist
+}
+
+type WordAlternatives struct { // the possible readings of a word in a text
+	Input      string      `json:"input"`
+	Candidates []Candidate `json:"candidates"`
+}
+
+// the most characters that are read differently from the first guess in a single candidate
+const maxCandidateChanges = 2
+
+// the most candidates returned for a word
+const MaxCandidates = 8
+
+// returns the possible francis-smith readings of a single lowercase word, best first
+// the first guess of the converter is always a candidate. if knownOnly is true and any candidates are in the wordlist, only those are returned
+func Candidates(inputStr string, orthographyChoice string, knownOnly bool) ([]Candidate, error) {
+	unifiedString, normalizeErr := NormalizeWord(inputStr, orthographyChoice)
+	if normalizeErr != nil {
+		return nil, normalizeErr
+	}
+	alternatives := make(map[rune][]string)
+	for _, ThisAmbiguity := range OrthographyDefinitions[orthographyChoice].Ambiguities {
+		alternatives[[]rune(ThisAmbiguity.From)[0]] = ThisAmbiguity.To
+	}
+
+	var ReadingCandidates []Candidate
+	seenReadings := make(map[string]bool) // readings that differ only in ways francis-smith does not write (e.g. voicing) are the same
+	addCandidate := func(unifiedRunes []rune, changes int) {
+		FrancisSmith := OrthographyDefinitions["francissmith"].EncodeString(string(unifiedRunes))
+		if seenReadings[FrancisSmith] {
+			return
+		}
+		seenReadings[FrancisSmith] = true
+		ReadingCandidates = append(ReadingCandidates, Candidate{string(unifiedRunes), FrancisSmith, changes, KnownWords.Contains(FrancisSmith)})
+	}
+
+	unifiedRunes := []rune(unifiedString)
+	addCandidate(unifiedRunes, 0)
+	var expand func(reading []rune, startIndex int, changes int)
+	expand = func(reading []rune, startIndex int, changes int) { // changes one more character after startIndex, in every way it can be changed
+		if changes == maxCandidateChanges {
+			return
+		}
+		for charIndex := startIndex; charIndex < len(reading); charIndex++ {
+			for _, alternative := range alternatives[unifiedRunes[charIndex]] {
+				newReading := append(append(append([]rune{}, reading[:charIndex]...), []rune(alternative)[0]), reading[charIndex+1:]...)
+				addCandidate(newReading, changes+1)
+				expand(newReading, charIndex+1, changes+1)
+			}
+		}
+	}
+	expand(unifiedRunes, 0, 0)
+
+	sort.SliceStable(ReadingCandidates, func(firstIndex, secondIndex int) bool {
+		if ReadingCandidates[firstIndex].Known != ReadingCandidates[secondIndex].Known {
+			return ReadingCandidates[firstIndex].Known
+		}
+		return ReadingCandidates[firstIndex].Changes < ReadingCandidates[secondIndex].Changes
+	})
+	if knownOnly && ReadingCandidates[0].Known {
+		knownCount := 0
+		for knownCount < len(ReadingCandidates) && ReadingCandidates[knownCount].Known {
+			knownCount++
+		}
+		ReadingCandidates = ReadingCandidates[:knownCount]
+	}
+	if len(ReadingCandidates) > MaxCandidates {
+		ReadingCandidates = ReadingCandidates[:MaxCandidates]
+	}
+	return ReadingCandidates, nil
+}
+
+// returns the possible readings of every word in a text that has more than one, or a known one if knownOnly is true
+// the text is split as in ConvertAllOptions with the same Options. orthographies without ambiguities (e.g. francis-smith) have no alternatives
+func AlternativesAll(inputStr string, orthographyChoice string, knownOnly bool, Options ConvertOptions) ([]WordAlternatives, error) {
+	Alternatives := []WordAlternatives{}
+	if orthographyChoice == "auto" {
+		orthographyChoice = DetectOrthography(inputStr).Orthography
+	}
+	if _, found := OrthographyNames[orthographyChoice]; !found {
+		return Alternatives, ErrUnknownOrthography
+	}
+	if inputStr == "" || len(OrthographyDefinitions[orthographyChoice].Ambiguities) == 0 {
+		return Alternatives, nil
+	}
+	conversionStringSlice, splitErr := splitConversionStrings(inputStr, orthographyChoice, Options)
+	if splitErr != nil {
+		return Alternatives, splitErr
+	}
+	for _, stringElement := range conversionStringSlice {
+		word := strings.TrimSpace(stringElement.InputString)
+		if stringElement.Escaped || stringElement.Punctuation || word == "" {
+			continue
+		}
+		ReadingCandidates, _ := Candidates(word, orthographyChoice, knownOnly)
+		if len(ReadingCandidates) > 1 || (knownOnly && ReadingCandidates[0].Known) { // a word filtered down to one known reading is still shown
+			Alternatives = append(Alternatives, WordAlternatives{word, ReadingCandidates})
+		}
+	}
+	return Alternatives, nil
+}
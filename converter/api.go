@@ -0,0 +1,155 @@
+// an http endpoint for converting text without the OrthoConverter page, for scripts, spreadsheets, etc.
+// /convert/api?text=...&from=listuguj&to=francissmith,metallic returns json
+// adding &format=text returns text/plain instead (one line per orthography, or just the text if there is one target)
+// adding &trace=true adds every rule that changed every word to the json
+// adding &check=true checks every word against the wordlist, suggesting the nearest known words for unknown ones
+// adding &syllables=true splits every word into syllables and marks the predicted stress (e.g. ˈke·salk)
+// adding &protect=true keeps numbers, links, e-mail addresses, and english and french words as they are,
+// with &convertwords=... for words to convert anyway and &keepwords=... for more words to keep (separated by commas)
+// escaped sequences are in {} unless other markers are given with &escapeopen=...&escapeclose=..., e.g. [[ and ]]; unbalanced markers return an error
+// errors are returned with a 400 status, as {"error": ..., "code": ...}, where the code is one of those of ErrorCode
+// adding &alternatives=true adds the possible francis-smith readings of ambiguous (pacifique and rand) words, and &known=true keeps only those in the wordlist
+
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type Warning struct { // a structured version of the disclaimers on the page
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type APIResponse struct { // what the endpoint returns as json
+	Input        string             `json:"input"`
+	From         string             `json:"from"`
+	Detection    *Detection         `json:"detection,omitempty"` // only if the source orthography was "auto"
+	Conversions  map[string]string  `json:"conversions"`
+	Warnings     []Warning          `json:"warnings"`
+	Trace        []WordTrace        `json:"trace,omitempty"`        // only if trace=true
+	Alternatives []WordAlternatives `json:"alternatives,omitempty"` // only if alternatives=true
+	Checks       []WordCheck        `json:"checks,omitempty"`       // only if check=true
+}
+
+type APIError struct { // returned with a 400 status if the request cannot be converted
+	Error string `json:"error"`
+	Code  string `json:"code"` // the kind of error, from ErrorCode, e.g. "empty_input"
+}
+
+// the same text as the tooltips on the page
+var (
+	PacifiqueWarning = Warning{"pacifique_tentative", "Pacifique orthography is difficult to accurately convert to other orthographies. Conversions are tentative."}
+	RandWarning      = Warning{"rand_in_progress", "Rand orthography is complex. Conversion to and from this orthography is a work in progress."}
+)
+
+// handles /convert/api
+func apiHandler(writer http.ResponseWriter, reader *http.Request) {
+	var Response APIResponse
+	Response.Input = reader.FormValue("text")
+	Response.From = reader.FormValue("from")
+	Response.Warnings = []Warning{}
+	Response.Conversions = make(map[string]string)
+	plainText := reader.FormValue("format") == "text"
+
+	if Response.From == "" || Response.From == "auto" { // if no orthography is given, detect it
+		Detection := DetectOrthography(Response.Input)
+		Response.Detection = &Detection
+		Response.From = Detection.Orthography
+	}
+
+	targets := readTargets(reader)
+	for _, target := range targets {
+		if _, found := OrthographyNames[target]; !found {
+			writeAPIError(writer, plainText, ErrorCode(ErrUnknownOrthography), fmt.Sprintf("unknown target orthography %q", target))
+			return
+		}
+	}
+
+	var Options ConvertOptions
+	Options.Syllables = reader.FormValue("syllables") == "true"
+	if reader.FormValue("protect") == "true" {
+		Options.Protect = NewProtection(reader.FormValue("convertwords"), reader.FormValue("keepwords"))
+	}
+	Options.Markers = EscapeMarkers{reader.FormValue("escapeopen"), reader.FormValue("escapeclose")}
+	if _, found := OrthographyNames[Response.From]; !found {
+		writeAPIError(writer, plainText, ErrorCode(ErrUnknownOrthography), fmt.Sprintf("unknown source orthography %q", Response.From))
+		return
+	}
+	OutputWords, convertErr := ConvertAllOptions(Response.Input, Response.From, Options)
+	if convertErr != nil { // text that cannot be converted, unbalanced escaped sequences, or markers that cannot be used
+		writeAPIError(writer, plainText, ErrorCode(convertErr), convertErr.Error())
+		return
+	}
+	for _, target := range targets {
+		Response.Conversions[target], _ = OutputWords.Get(target)
+	}
+	var explainErr error // the text is split the same way as it was converted, so this is not expected to fail
+	if reader.FormValue("trace") == "true" {
+		Response.Trace, explainErr = TraceAll(Response.Input, Response.From, targets, Options)
+	}
+	if reader.FormValue("check") == "true" && explainErr == nil {
+		Response.Checks, explainErr = CheckAll(Response.Input, Response.From, Options)
+	}
+	if reader.FormValue("alternatives") == "true" && explainErr == nil {
+		Response.Alternatives, explainErr = AlternativesAll(Response.Input, Response.From, reader.FormValue("known") == "true", Options)
+	}
+	if explainErr != nil {
+		writeAPIError(writer, plainText, ErrorCode(explainErr), explainErr.Error())
+		return
+	}
+	if OutputWords.PacifiqueDisclaimer {
+		Response.Warnings = append(Response.Warnings, PacifiqueWarning)
+	}
+	if OutputWords.RandDisclaimer {
+		Response.Warnings = append(Response.Warnings, RandWarning)
+	}
+
+	if plainText {
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if len(targets) == 1 { // a single target is just the converted text, so it can be piped straight into other tools
+			fmt.Fprintln(writer, Response.Conversions[targets[0]])
+			return
+		}
+		for _, target := range targets {
+			fmt.Fprintf(writer, "%s: %s\n", target, Response.Conversions[target])
+		}
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encodeErr := json.NewEncoder(writer).Encode(Response)
+	if encodeErr != nil {
+		fmt.Println(encodeErr)
+	}
+}
+
+// reads the target orthographies, given as repeated "to" values or a comma-separated list. no targets means all of them
+func readTargets(reader *http.Request) []string {
+	var targets []string
+	reader.ParseForm()
+	for _, value := range reader.Form["to"] {
+		for _, target := range strings.Split(value, ",") {
+			if target = strings.TrimSpace(target); target != "" {
+				targets = append(targets, target)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		targets = Orthographies
+	}
+	return targets
+}
+
+// writes an error with a 400 status, as json or as text
+func writeAPIError(writer http.ResponseWriter, plainText bool, code string, message string) {
+	if plainText {
+		http.Error(writer, message, http.StatusBadRequest)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(writer).Encode(APIError{message, code})
+}
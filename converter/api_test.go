@@ -0,0 +1,71 @@
+// tests for the conversion api
+
+package converter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// asks the api for a conversion, and returns its json response
+func requestAPI(t *testing.T, query url.Values) APIResponse {
+	recorder := httptest.NewRecorder()
+	apiHandler(recorder, httptest.NewRequest(http.MethodGet, "/convert/api?"+query.Encode(), nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("%s: status %d: %s", query.Encode(), recorder.Code, recorder.Body.String())
+	}
+	var Response APIResponse
+	unmarshalErr := json.Unmarshal(recorder.Body.Bytes(), &Response)
+	if unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+	return Response
+}
+
+// returns the words that were traced and checked
+func tracedAndChecked(Response APIResponse) ([]string, []string) {
+	var traced, checked []string
+	for _, Trace := range Response.Trace {
+		traced = append(traced, Trace.Input)
+	}
+	for _, Check := range Response.Checks {
+		checked = append(checked, Check.Input)
+	}
+	return traced, checked
+}
+
+// words kept as they are by protect=true are not traced, checked or given alternatives, as they are not converted
+func TestAPIProtectedWords(t *testing.T) {
+	Response := requestAPI(t, url.Values{"text": {"teluisit the"}, "from": {"francissmith"}, "to": {"listuguj"},
+		"protect": {"true"}, "trace": {"true"}, "check": {"true"}})
+	if Response.Conversions["listuguj"] != "teluisit the" {
+		t.Errorf("converted to %q", Response.Conversions["listuguj"])
+	}
+	traced, checked := tracedAndChecked(Response)
+	if len(traced) != 1 || traced[0] != "teluisit" || len(checked) != 1 || checked[0] != "teluisit" {
+		t.Errorf("traced %q and checked %q, want only teluisit", traced, checked)
+	}
+	Response = requestAPI(t, url.Values{"text": {"the elues"}, "from": {"pacifique"}, "to": {"francissmith"},
+		"protect": {"true"}, "alternatives": {"true"}})
+	for _, Alternative := range Response.Alternatives {
+		if Alternative.Input == "the" {
+			t.Errorf("the protected word %q has alternatives", Alternative.Input)
+		}
+	}
+}
+
+// other escape markers are used to trace and check the words, as they are to convert them
+func TestAPIEscapeMarkers(t *testing.T) {
+	Response := requestAPI(t, url.Values{"text": {"[[Ontario]] teluisit {"}, "from": {"francissmith"}, "to": {"listuguj"},
+		"escapeopen": {"[["}, "escapeclose": {"]]"}, "trace": {"true"}, "check": {"true"}})
+	if Response.Conversions["listuguj"] != "Ontario teluisit {" {
+		t.Errorf("converted to %q", Response.Conversions["listuguj"])
+	}
+	traced, checked := tracedAndChecked(Response)
+	if len(traced) != 1 || traced[0] != "teluisit" || len(checked) != 1 || checked[0] != "teluisit" {
+		t.Errorf("traced %q and checked %q, want only teluisit", traced, checked)
+	}
+}
@@ -0,0 +1,160 @@
+// guesses which orthography a text is written in
+// every orthography has characters and sequences that it uses and others never do (g vs k, ɨ vs ', rand diacritics, pacifique ô/tj, metallic voicing, ipa symbols)
+// each of these adds to or takes away from the score of every orthography, and the scores are turned into a confidence
+
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+)
+
+type Detection struct { // the result of detecting the orthography of a text
+	Orthography string             `json:"orthography"` // the most likely orthography
+	Confidence  float64            `json:"confidence"`  // from 0 to 1
+	Scores      map[string]float64 `json:"scores"`      // the confidence for every orthography
+}
+
+type orthographyFeature struct { // a sequence that tells orthographies apart, and how much it counts for each
+	Sequence string
+	Weights  map[string]float64
+}
+
+// the order of this list does not matter, every sequence is counted in the whole text
+var orthographyFeatures = []orthographyFeature{
+	// schwa
+	{"ɨ", map[string]float64{"francissmith": 3, "lexicon": 3}},
+	{"*", map[string]float64{"francissmith": 2, "lexicon": 2}},
+	{"ê", map[string]float64{"metallic": 3}},
+	{"e!", map[string]float64{"metallic": 2}},
+	{"ŭ", map[string]float64{"rand": 3}},
+	{"u/", map[string]float64{"rand": 2}},
+	// listuguj writes schwa as an apostrophe after a consonant
+	{"p'", map[string]float64{"listuguj": 3}},
+	{"t'", map[string]float64{"listuguj": 3}},
+	{"g'", map[string]float64{"listuguj": 3}},
+	{"s'", map[string]float64{"listuguj": 3}},
+	{"j'", map[string]float64{"listuguj": 3}},
+	{"q'", map[string]float64{"listuguj": 3}},
+	{"l'", map[string]float64{"listuguj": 3}},
+	{"m'", map[string]float64{"listuguj": 3}},
+	{"n'", map[string]float64{"listuguj": 3}},
+	// vowel length
+	{"a'", map[string]float64{"francissmith": 1, "listuguj": 1, "lexicon": -1, "pacifique": -1, "metallic": -0.5}},
+	{"e'", map[string]float64{"francissmith": 1, "listuguj": 1, "lexicon": -1, "pacifique": -1, "metallic": -0.5}},
+	{"i'", map[string]float64{"francissmith": 1, "listuguj": 1, "lexicon": -1, "pacifique": -1, "metallic": -0.5}},
+	{"o'", map[string]float64{"francissmith": 1, "listuguj": 1, "lexicon": -1, "pacifique": -1, "metallic": -0.5}},
+	{"u'", map[string]float64{"francissmith": 1, "listuguj": 1, "lexicon": -1, "pacifique": -1, "metallic": -0.5}},
+	{"a:", map[string]float64{"lexicon": 3, "rand": 1}},
+	{"e:", map[string]float64{"lexicon": 3, "rand": 1}},
+	{"i:", map[string]float64{"lexicon": 3, "rand": 1}},
+	{"o:", map[string]float64{"lexicon": 3, "rand": 1}},
+	{"u:", map[string]float64{"lexicon": 3, "rand": 1}},
+	{"à", map[string]float64{"metallic": 2, "francissmith": 0.5}},
+	{"è", map[string]float64{"metallic": 2, "francissmith": 0.5}},
+	{"ì", map[string]float64{"metallic": 2, "francissmith": 0.5}},
+	{"ò", map[string]float64{"metallic": 2, "francissmith": 0.5}},
+	{"ù", map[string]float64{"metallic": 2, "francissmith": 0.5}},
+	{"aa", map[string]float64{"pacifique": 1, "rand": 1}},
+	{"ee", map[string]float64{"pacifique": 0.5, "rand": 1}},
+	{"oo", map[string]float64{"pacifique": 0.5, "rand": 1}},
+	// rand diacritics
+	{"ă", map[string]float64{"rand": 3}},
+	{"â", map[string]float64{"rand": 3}},
+	{"ā", map[string]float64{"rand": 3}},
+	{"ä", map[string]float64{"rand": 3}},
+	{"ĕ", map[string]float64{"rand": 3}},
+	{"ë", map[string]float64{"rand": 3}},
+	{"ĭ", map[string]float64{"rand": 3}},
+	{"ï", map[string]float64{"rand": 3}},
+	{"ŏ", map[string]float64{"rand": 3}},
+	{"ō", map[string]float64{"rand": 3}},
+	{"ö", map[string]float64{"rand": 3}},
+	{"ü", map[string]float64{"rand": 3}},
+	{"ç", map[string]float64{"rand": 3}},
+	{"a/", map[string]float64{"rand": 2}},
+	{"a-", map[string]float64{"rand": 2}},
+	{"o-", map[string]float64{"rand": 2}},
+	{"h", map[string]float64{"rand": 2}},
+	// pacifique
+	{"ô", map[string]float64{"pacifique": 4}},
+	{"o!", map[string]float64{"pacifique": 3}},
+	{"tj", map[string]float64{"pacifique": 4.5}},
+	{"j", map[string]float64{"pacifique": -1.5}}, // but never j on its own
+	{"oa", map[string]float64{"pacifique": 1.5}}, // pacifique writes w and gw as o and go, so o comes before a vowel
+	{"oe", map[string]float64{"pacifique": 1.5}},
+	{"oi", map[string]float64{"pacifique": 1.5}},
+	{"'", map[string]float64{"pacifique": -2}}, // and never writes an apostrophe, u or w
+	{"u", map[string]float64{"pacifique": -2}},
+	// ipa
+	{"ə", map[string]float64{"ipa": 3, "ipanarrow": 3}},
+	{"ː", map[string]float64{"ipa": 3, "ipanarrow": 3, "metallic": -2}},
+	{"x", map[string]float64{"ipa": 2, "ipanarrow": 2}},
+	{"ʷ", map[string]float64{"ipa": 3, "ipanarrow": 3}},
+	{"tʃ", map[string]float64{"ipa": 2, "ipanarrow": 1}},
+	{"dʒ", map[string]float64{"ipanarrow": 3}},
+	{"\u0329", map[string]float64{"ipa": 2, "ipanarrow": 2}}, // the syllabic mark under l, n and m
+	{"\u0325", map[string]float64{"ipanarrow": 3}},           // the partial voicing ring under d and b
+	{"\u030a", map[string]float64{"ipanarrow": 3}},           // and over ɡ
+	{"ɡ", map[string]float64{"ipanarrow": 3}},
+	// velars and voicing
+	{"k", map[string]float64{"francissmith": 1, "lexicon": 1, "ipa": 1, "ipanarrow": 1, "rand": 0.5, "metallic": 0.5, "listuguj": -2, "pacifique": -2}},
+	{"g", map[string]float64{"listuguj": 1, "pacifique": 1.3, "metallic": 0.5, "rand": 0.3, "francissmith": -2, "lexicon": -2, "ipa": -2, "ipanarrow": -2}},
+	{"kw", map[string]float64{"francissmith": 1, "lexicon": 1}},
+	{"gw", map[string]float64{"listuguj": 1, "metallic": 1, "rand": 1}},
+	{"b", map[string]float64{"metallic": 1.5, "ipanarrow": 1.5, "francissmith": -1, "listuguj": -1, "lexicon": -1, "pacifique": -1, "ipa": -1}},
+	{"d", map[string]float64{"metallic": 1.5, "ipanarrow": 1.5, "francissmith": -1, "listuguj": -1, "lexicon": -1, "pacifique": -1, "ipa": -1}},
+	{"ch", map[string]float64{"metallic": 1, "rand": 1}},
+	{"q", map[string]float64{"francissmith": 0.3, "listuguj": 0.3, "lexicon": 0.3, "metallic": 0.3, "pacifique": -1, "rand": -1, "ipa": -1, "ipanarrow": -1}},
+	// semivowels
+	{"y", map[string]float64{"francissmith": 0.5, "lexicon": 0.5, "metallic": 0.5, "listuguj": -1, "pacifique": -1, "ipa": -1, "ipanarrow": -1}},
+	{"w", map[string]float64{"pacifique": -2}},
+}
+
+// scores the input text against every orthography and returns the most likely one
+// escaped sequences in {} are not counted
+func DetectOrthography(inputStr string) Detection {
+	var OutputDetection Detection
+	scores := make(map[string]float64)
+	inputStr = NormalizeUnicode(strings.ToLower(removeEscapedSequences(inputStr)))
+	for _, feature := range orthographyFeatures {
+		count := float64(strings.Count(inputStr, feature.Sequence))
+		for orthography, weight := range feature.Weights {
+			scores[orthography] += count * weight
+		}
+	}
+
+	// turn the scores into confidences that add up to 1 (a softmax), so that equal scores give equal confidence
+	var total float64
+	for _, orthography := range Orthographies {
+		total += math.Exp(scores[orthography])
+	}
+	OutputDetection.Scores = make(map[string]float64)
+	for _, orthography := range Orthographies { // in the order of Orthographies, so that ties go to the first (francis-smith)
+		OutputDetection.Scores[orthography] = math.Exp(scores[orthography]) / total
+		if OutputDetection.Orthography == "" || OutputDetection.Scores[orthography] > OutputDetection.Confidence {
+			OutputDetection.Orthography = orthography
+			OutputDetection.Confidence = OutputDetection.Scores[orthography]
+		}
+	}
+	return OutputDetection
+}
+
+// drops escaped sequences so that names, dates, etc. do not count towards any orthography
+// unbalanced markers are not an error here: the text is still detected, as well as it can be
+func removeEscapedSequences(inputStr string) string {
+	Pieces, _ := splitEscapedSequences(inputStr, DefaultEscapeMarkers)
+	return unescapedText(Pieces)
+}
+
+// returns the detection for the "text" value as json
+func detectHandler(writer http.ResponseWriter, reader *http.Request) {
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encodeErr := json.NewEncoder(writer).Encode(DetectOrthography(reader.FormValue("text")))
+	if encodeErr != nil {
+		fmt.Println(encodeErr)
+	}
+}
@@ -0,0 +1,102 @@
+// tests for DetectOrthography: the conjugated forms of the model verbs, written in every orthography, are detected as written in it
+// the forms come from the conjugator's golden pages (bescherelle/testdata/paradigms), every formStride-th form so that go test stays quick
+
+package converter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// how many forms are skipped between two that are tested
+const formStride = 20
+
+// the fields of a golden page that hold its forms
+type goldenPage struct {
+	Tables []struct {
+		Rows []struct {
+			Forms []map[string]string `json:"forms"`
+		} `json:"rows"`
+	} `json:"tables"`
+}
+
+// reads every francis-smith form from the golden pages, once each
+func modelVerbForms(t *testing.T) []string {
+	pagePaths, globErr := filepath.Glob(filepath.Join("..", "bescherelle", "testdata", "paradigms", "*.json"))
+	if globErr != nil || len(pagePaths) == 0 {
+		t.Fatalf("no golden pages to read the model verbs from: %v", globErr)
+	}
+	var forms []string
+	seen := make(map[string]bool)
+	for _, pagePath := range pagePaths {
+		pageBytes, readErr := os.ReadFile(pagePath)
+		if readErr != nil {
+			t.Fatal(readErr)
+		}
+		var Page goldenPage
+		unmarshalErr := json.Unmarshal(pageBytes, &Page)
+		if unmarshalErr != nil {
+			t.Fatalf("%s: %v", pagePath, unmarshalErr)
+		}
+		for _, Table := range Page.Tables {
+			for _, Row := range Table.Rows {
+				for _, Form := range Row.Forms {
+					form := Form["francissmith"]
+					if form != "" && !seen[form] {
+						seen[form] = true
+						forms = append(forms, form)
+					}
+				}
+			}
+		}
+	}
+	return forms
+}
+
+// a form counts as detected if it comes back as the orthography it is written in, or as one that writes it the same way
+// (e.g. amalkan is the same in francis-smith and lexicon, and nothing in the text can tell them apart)
+func TestDetectOrthography(t *testing.T) {
+	minimumAccuracy := []struct {
+		orthography string
+		accuracy    float64
+	}{
+		{"francissmith", 0.99},
+		{"listuguj", 0.95},
+		{"pacifique", 0.9},
+		{"rand", 0.95},
+		{"lexicon", 0.99},
+		{"metallic", 0.95},
+		{"ipa", 0.95},
+		{"ipanarrow", 0.95},
+	}
+	forms := modelVerbForms(t)
+	for _, Minimum := range minimumAccuracy {
+		t.Run(Minimum.orthography, func(t *testing.T) {
+			detected, tested := 0, 0
+			for formIndex := 0; formIndex < len(forms); formIndex += formStride {
+				writtenStr, convertErr := Convert(forms[formIndex], "francissmith", Minimum.orthography)
+				if convertErr != nil {
+					t.Fatalf("%q: %v", forms[formIndex], convertErr)
+				}
+				tested++
+				detectedOrthography := DetectOrthography(writtenStr).Orthography
+				if detectedOrthography == Minimum.orthography {
+					detected++
+					continue
+				}
+				detectedStr, _ := Convert(forms[formIndex], "francissmith", detectedOrthography)
+				if detectedStr == writtenStr {
+					detected++
+					continue
+				}
+				t.Logf("%q is detected as %s", writtenStr, detectedOrthography)
+			}
+			accuracy := float64(detected) / float64(tested)
+			if accuracy < Minimum.accuracy {
+				t.Errorf("%d of %d forms detected (%.2f), at least %.2f expected", detected, tested, accuracy, Minimum.accuracy)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Package converter converts Mi'kmaw text between the Francis-Smith, Listuguj, Pacifique, Rand, Lexicon, and Metallic orthographies, and writes it in phonemic and narrow IPA.
+//
+// It is the package behind the OrthoConverter page, and can be imported by other programs.
+// Orthographies are named by the values in Orthographies (e.g. "francissmith", "listuguj").
+//
+//	listuguj, err := converter.Convert("Wejia'p {Ontario}ek", "francissmith", "listuguj")
+//	everyOrthography, err := converter.ConvertAll("gesalg'p", "auto")
+//
+// Convert and ConvertAll treat text the way the page does: only words are converted, so punctuation, whitespace, digits,
+// and anything between { and } are left as they are, and words keep a capital initial letter or ALL CAPS. Use "auto" as the source orthography to detect it
+// with DetectOrthography. ConvertWord converts a single lowercase word with no escaping. Escaped sequences can span lines and be nested,
+// \{ and \} are literal braces, other markers can be set in ConvertOptions, and unbalanced markers return an *EscapeError.
+//
+// Text is checked with ValidateText, and a single word with ValidateWord: empty, too long, or unreadable input returns ErrEmptyInput,
+// or an *InputError for ErrInputTooLong or ErrInvalidCharacter, which can be told apart with errors.Is. ErrorCode names them for the api.
+//
+// ConvertAllOptions can also keep numbers, links, e-mail addresses, and common English and French words (foreignwords.txt)
+// as they are without them being put in {}, with lists of words to convert or keep anyway made by NewProtection.
+//
+// Each orthography is a list of ordered rewrite rules in a data file in converter/orthographies (the format is described in rules.go).
+// The files are built into the package, and ConverterInit also reads the folder on disk, so an orthography can be added or changed
+// without changing any Go code. Other programs can do the same with ParseOrthography and AddOrthography, and Orthography.Check
+// runs the examples in a file, so a rule set can be tried on its own.
+//
+// NormalizeUnicode composes diacritics typed as separate characters and reads lookalike apostrophes and barred i's as the real ones;
+// every word is normalized this way before it is converted or conjugated. Sequences that are easy to type stand for special characters (e.g. u/ for ŭ in Rand, o! for ô in Pacifique, * for ɨ in Francis-Smith).
+// The table is in substitutions.json, Substitute applies it to a word, and /convert/substitute previews it while text is typed.
+//
+// KnownWords is a list of Francis-Smith words (wordlist.txt, or a dictionary export named by the MIKMAW_WORDLIST environment
+// variable). CheckAll marks every converted word as known or not and suggests the nearest known words, and Candidates ranks
+// the possible readings of Pacifique and Rand words by it.
+//
+// Syllabify splits a word in unified orthography into syllables and predicts its stress, and ConvertAllSyllables and SyllabifyWord
+// write every word that way (e.g. "ˈke·salk") in any orthography.
+//
+// ConvertDocument converts the readable text of a plain text, Markdown, HTML, SRT or VTT document and leaves its markup,
+// code and timestamps as they are. ConvertOfficeDocument does the same for the text of .docx and .odt files, keeping their formatting,
+// and ConvertFile takes any of these formats. It is behind the file upload on the page, and cmd/convertfile does the same from the command line.
+//
+// ConverterInit registers the OrthoConverter page with net/http; it is not needed to use the functions above.
+package converter
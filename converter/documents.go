@@ -0,0 +1,412 @@
+// converts whole documents: plain text, markdown, html, and srt/vtt subtitles
+// only the text people read is converted. tags, comments, entities, scripts and styles in html,
+// code blocks, code spans, link addresses and emphasis markers in markdown, and cue numbers, timestamps and settings in subtitles
+// are left exactly as they are, so the converted file can be used in place of the original
+// used by the file upload on the OrthoConverter page (/convert/document) and by cmd/convertfile
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+type documentSegment struct { // a piece of a document, and whether it is text to be converted
+	Text     string
+	Readable bool
+}
+
+// the formats a document can be in, by file extension
+var DocumentFormats = map[string]string{
+	".txt":      "txt",
+	".text":     "txt",
+	".md":       "md",
+	".markdown": "md",
+	".html":     "html",
+	".htm":      "html",
+	".srt":      "srt",
+	".vtt":      "vtt",
+	".docx":     "docx",
+	".odt":      "odt",
+}
+
+// the content type of a converted document of each format, for downloads
+var documentContentTypes = map[string]string{
+	"txt":  "text/plain; charset=utf-8",
+	"md":   "text/markdown; charset=utf-8",
+	"html": "text/html; charset=utf-8",
+	"srt":  "application/x-subrip; charset=utf-8",
+	"vtt":  "text/vtt; charset=utf-8",
+	"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"odt":  "application/vnd.oasis.opendocument.text",
+}
+
+// the largest document that can be uploaded
+const MaxDocumentSize = 10 << 20
+
+// the most the parts of a .docx or .odt file that hold text can be together once they are uncompressed,
+// so that a small zip file cannot fill the memory of the server
+const MaxUncompressedDocumentSize = 50 << 20
+
+var ErrDocumentTooLarge = errors.New("document too large")
+
+// html elements whose contents are not read as text
+var unreadableElements = []string{"script", "style", "code", "pre"}
+
+// html character references, e.g. &amp; and &#233;
+var htmlEntity = regexp.MustCompile(`^&(#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+
+// returns the format of a document from its file name, e.g. "md" for story.md
+func DocumentFormat(fileName string) (string, error) {
+	format, found := DocumentFormats[strings.ToLower(path.Ext(fileName))]
+	if !found {
+		return "", fmt.Errorf("unsupported document type %q: use .txt, .md, .html, .srt, .vtt, .docx or .odt", path.Ext(fileName))
+	}
+	return format, nil
+}
+
+// ConvertFile converts a document file in any of the formats in DocumentFormats, text or word processor, and returns the converted file
+func ConvertFile(contents []byte, format string, fromOrthography string, toOrthography string) ([]byte, error) {
+	if _, found := officeFormats[format]; found {
+		return ConvertOfficeDocument(contents, format, fromOrthography, toOrthography)
+	}
+	convertedStr, convertErr := ConvertDocument(string(contents), format, fromOrthography, toOrthography)
+	return []byte(convertedStr), convertErr
+}
+
+// ConvertDocument converts the readable text of a document in the given format ("txt", "md", "html", "srt" or "vtt")
+// from one orthography to another, leaving markup, code and timestamps as they are.
+// fromOrthography may be "auto", in which case the orthography is detected from the readable text of the whole document.
+func ConvertDocument(contents string, format string, fromOrthography string, toOrthography string) (string, error) {
+	if _, found := OrthographyNames[toOrthography]; !found {
+		return "", ErrUnknownOrthography
+	}
+	Segments, splitErr := splitDocument(contents, format)
+	if splitErr != nil {
+		return "", splitErr
+	}
+	if fromOrthography == "auto" { // detect once for the whole document, so that every segment is read the same way
+		var readableText strings.Builder
+		for _, Segment := range Segments {
+			if Segment.Readable {
+				readableText.WriteString(Segment.Text)
+			}
+		}
+		fromOrthography = DetectOrthography(readableText.String()).Orthography
+	}
+	if _, found := OrthographyNames[fromOrthography]; !found {
+		return "", ErrUnknownOrthography
+	}
+
+	var outputStr strings.Builder
+	Scanner, _ := newEscapeScanner(DefaultEscapeMarkers) // one for the whole document, so that an escaped sequence can go on past a tag
+	for _, Segment := range Segments {
+		if !Segment.Readable || strings.TrimSpace(Segment.Text) == "" {
+			Scanner.advance(Segment.Text) // so that errors give the line in the document
+			outputStr.WriteString(Segment.Text)
+			continue
+		}
+		convertedStr, convertErr := Scanner.convert(Segment.Text, fromOrthography, toOrthography)
+		if convertErr != nil {
+			return "", convertErr
+		}
+		outputStr.WriteString(convertedStr)
+	}
+	return outputStr.String(), Scanner.finish()
+}
+
+// converts a piece of a document from one orthography to another, keeping the scanner's place
+// so that an escaped sequence that is not closed in this piece goes on into the next one
+func (Scanner *escapeScanner) convert(inputStr string, fromOrthography string, toOrthography string) (string, error) {
+	Tokens, splitErr := Scanner.tokenize(inputStr, fromOrthography, nil)
+	if splitErr != nil {
+		return "", splitErr
+	}
+	outputStr, _ := convertStrings(Output{}, Tokens, fromOrthography, false).Get(toOrthography)
+	return outputStr, nil
+}
+
+// splits a document into readable text and everything else
+func splitDocument(contents string, format string) ([]documentSegment, error) {
+	switch format {
+	case "txt":
+		return []documentSegment{{contents, true}}, nil
+	case "md":
+		return splitMarkdown(contents), nil
+	case "html":
+		return splitHTML(contents), nil
+	case "srt", "vtt":
+		return splitSubtitles(contents), nil
+	}
+	return nil, fmt.Errorf("unsupported document format %q", format)
+}
+
+// adds a segment, joining it to the last one if they are both readable or both not
+func addSegment(Segments []documentSegment, text string, readable bool) []documentSegment {
+	if text == "" {
+		return Segments
+	}
+	if len(Segments) > 0 && Segments[len(Segments)-1].Readable == readable {
+		Segments[len(Segments)-1].Text += text
+		return Segments
+	}
+	return append(Segments, documentSegment{text, readable})
+}
+
+// splits html into text, and tags, comments, character references, and the contents of scripts, styles and code
+func splitHTML(contents string) []documentSegment {
+	var Segments []documentSegment
+	for len(contents) > 0 {
+		if strings.HasPrefix(contents, "<!--") { // comments
+			commentEnd := strings.Index(contents, "-->")
+			if commentEnd < 0 {
+				commentEnd = len(contents) - len("-->")
+			}
+			Segments = addSegment(Segments, contents[:commentEnd+len("-->")], false)
+			contents = contents[commentEnd+len("-->"):]
+		} else if tagLength := htmlTagLength(contents); tagLength > 0 { // tags, and everything up to the end of script, style and code elements
+			tagName := htmlTagName(contents[:tagLength])
+			for _, elementName := range unreadableElements {
+				if tagName == elementName {
+					closingTag := strings.Index(strings.ToLower(contents[tagLength:]), "</"+elementName)
+					if closingTag < 0 {
+						closingTag = len(contents) - tagLength
+					}
+					tagLength += closingTag
+					break
+				}
+			}
+			Segments = addSegment(Segments, contents[:tagLength], false)
+			contents = contents[tagLength:]
+		} else if entity := htmlEntity.FindString(contents); entity != "" { // &amp; etc. would otherwise be read as words
+			Segments = addSegment(Segments, entity, false)
+			contents = contents[len(entity):]
+		} else {
+			textEnd := strings.IndexAny(contents[1:], "<&") + 1
+			if textEnd == 0 {
+				textEnd = len(contents)
+			}
+			Segments = addSegment(Segments, contents[:textEnd], true)
+			contents = contents[textEnd:]
+		}
+	}
+	return Segments
+}
+
+// returns the length of the html tag at the start of the text, or 0 if it does not start with one
+// a < that is not followed by a letter, / or ! is just text (e.g. "1 < 2")
+func htmlTagLength(contents string) int {
+	if len(contents) < 2 || contents[0] != '<' {
+		return 0
+	}
+	if !strings.ContainsRune("/!?", rune(contents[1])) && !isASCIILetter(contents[1]) {
+		return 0
+	}
+	tagEnd := strings.IndexByte(contents, '>')
+	if tagEnd < 0 {
+		return 0
+	}
+	return tagEnd + 1
+}
+
+// returns the lowercase name of an opening tag, e.g. "pre" for <pre class="x">, or "" for closing tags, comments and doctypes
+func htmlTagName(tag string) string {
+	nameEnd := 1
+	for nameEnd < len(tag) && isASCIILetter(tag[nameEnd]) {
+		nameEnd++
+	}
+	return strings.ToLower(tag[1:nameEnd])
+}
+
+// returns true if the byte is a letter from a to z, in either case
+func isASCIILetter(char byte) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+// splits markdown into text, and fenced code blocks, code spans, link addresses, reference definitions, html tags, and emphasis markers
+func splitMarkdown(contents string) []documentSegment {
+	var Segments []documentSegment
+	inFence := ""
+	for _, line := range strings.SplitAfter(contents, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if inFence != "" { // inside a fenced code block, until the same fence closes it
+			Segments = addSegment(Segments, line, false)
+			if strings.HasPrefix(trimmedLine, inFence) {
+				inFence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmedLine, "```") || strings.HasPrefix(trimmedLine, "~~~") {
+			inFence = trimmedLine[:3]
+			Segments = addSegment(Segments, line, false)
+			continue
+		}
+		if markdownReference.MatchString(line) { // [name]: https://...
+			Segments = addSegment(Segments, line, false)
+			continue
+		}
+		for _, Segment := range splitMarkdownLine(line) {
+			Segments = addSegment(Segments, Segment.Text, Segment.Readable)
+		}
+	}
+	return Segments
+}
+
+// link reference definitions, which are not shown
+var markdownReference = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s`)
+
+// splits one line of markdown outside a code block
+func splitMarkdownLine(line string) []documentSegment {
+	var Segments []documentSegment
+	for len(line) > 0 {
+		switch {
+		case line[0] == '`': // a code span, closed by the same number of backticks
+			fenceLength := len(line) - len(strings.TrimLeft(line, "`"))
+			spanEnd := strings.Index(line[fenceLength:], line[:fenceLength])
+			if spanEnd < 0 { // backticks that are never closed are just backticks
+				Segments = addSegment(Segments, line[:fenceLength], false)
+				line = line[fenceLength:]
+				continue
+			}
+			Segments = addSegment(Segments, line[:fenceLength+spanEnd+fenceLength], false)
+			line = line[fenceLength+spanEnd+fenceLength:]
+		case strings.HasPrefix(line, "]("): // the address of a link or image
+			addressEnd := strings.IndexByte(line, ')')
+			if addressEnd < 0 {
+				addressEnd = len(line) - 1
+			}
+			Segments = addSegment(Segments, line[:addressEnd+1], false)
+			line = line[addressEnd+1:]
+		case htmlTagLength(line) > 0: // html tags and autolinks (<https://...>)
+			tagLength := htmlTagLength(line)
+			Segments = addSegment(Segments, line[:tagLength], false)
+			line = line[tagLength:]
+		case line[0] == '*' || line[0] == '_': // emphasis, unless it is a schwa between letters (k*p)
+			markerLength := len(line) - len(strings.TrimLeft(line, line[:1]))
+			previousSegment := ""
+			if len(Segments) > 0 {
+				previousSegment = Segments[len(Segments)-1].Text
+			}
+			if line[0] == '*' && markerLength == 1 && endsWithLetter(previousSegment) && startsWithLetter(line[1:]) {
+				Segments = addSegment(Segments, "*", true)
+			} else {
+				Segments = addSegment(Segments, line[:markerLength], false)
+			}
+			line = line[markerLength:]
+		default:
+			textEnd := strings.IndexAny(line[1:], "`]<*_") + 1
+			if textEnd == 0 {
+				textEnd = len(line)
+			}
+			Segments = addSegment(Segments, line[:textEnd], true)
+			line = line[textEnd:]
+		}
+	}
+	return Segments
+}
+
+// returns true if the text begins with a character that can be part of a word
+func startsWithLetter(inputStr string) bool {
+	for _, char := range inputStr {
+		return isWordCharacter([]rune{char}, 0, false, false, "")
+	}
+	return false
+}
+
+// returns true if the text ends with a character that can be part of a word
+func endsWithLetter(inputStr string) bool {
+	inputRunes := []rune(inputStr)
+	return len(inputRunes) > 0 && isWordCharacter(inputRunes[len(inputRunes)-1:], 0, false, false, "")
+}
+
+// splits srt or vtt subtitles into the text of the cues, and everything else
+// the files are blocks separated by blank lines. in a cue, the lines up to and including the timestamp (-->) are not text;
+// blocks that are the vtt header, notes, styles or regions are not text at all
+func splitSubtitles(contents string) []documentSegment {
+	var Segments []documentSegment
+	lines := strings.SplitAfter(contents, "\n")
+	blockStart := 0
+	for blockStart < len(lines) {
+		blockEnd := blockStart
+		for blockEnd < len(lines) && strings.TrimSpace(lines[blockEnd]) != "" {
+			blockEnd++
+		}
+		if blockEnd == blockStart { // a blank line between blocks
+			Segments = addSegment(Segments, lines[blockStart], false)
+			blockStart++
+			continue
+		}
+		block := lines[blockStart:blockEnd]
+		timestampLine := -1
+		for lineIndex, line := range block {
+			if strings.Contains(line, "-->") {
+				timestampLine = lineIndex
+				break
+			}
+		}
+		firstWord := strings.Fields(block[0])[0]
+		if timestampLine < 0 || firstWord == "WEBVTT" || firstWord == "NOTE" || firstWord == "STYLE" || firstWord == "REGION" {
+			Segments = addSegment(Segments, strings.Join(block, ""), false)
+		} else {
+			Segments = addSegment(Segments, strings.Join(block[:timestampLine+1], ""), false)
+			for _, line := range block[timestampLine+1:] {
+				for _, Segment := range splitHTML(line) { // cue text can have tags, e.g. <i> and <00:00:01.000>
+					Segments = addSegment(Segments, Segment.Text, Segment.Readable)
+				}
+			}
+		}
+		blockStart = blockEnd
+	}
+	return Segments
+}
+
+// converts an uploaded document, and returns the converted file as a download
+func documentHandler(writer http.ResponseWriter, reader *http.Request) {
+	if reader.Method != http.MethodPost {
+		http.Redirect(writer, reader, "/convert", http.StatusSeeOther)
+		return
+	}
+	reader.Body = http.MaxBytesReader(writer, reader.Body, MaxDocumentSize)
+	uploadedFile, fileHeader, fileErr := reader.FormFile("document")
+	if fileErr != nil {
+		http.Error(writer, fmt.Sprintf("no document was uploaded: %v", fileErr), http.StatusBadRequest)
+		return
+	}
+	defer uploadedFile.Close()
+	format, formatErr := DocumentFormat(fileHeader.Filename)
+	if formatErr != nil {
+		http.Error(writer, formatErr.Error(), http.StatusBadRequest)
+		return
+	}
+	contents, readErr := io.ReadAll(uploadedFile)
+	if readErr != nil {
+		http.Error(writer, readErr.Error(), http.StatusBadRequest)
+		return
+	}
+	toOrthography := reader.FormValue("to")
+	convertedFile, convertErr := ConvertFile(contents, format, reader.FormValue("from"), toOrthography)
+	if errors.Is(convertErr, ErrDocumentTooLarge) {
+		http.Error(writer, convertErr.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if convertErr != nil {
+		http.Error(writer, convertErr.Error(), http.StatusBadRequest)
+		return
+	}
+	writer.Header().Set("Content-Type", documentContentTypes[format])
+	writer.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": ConvertedFileName(fileHeader.Filename, toOrthography)}))
+	writer.Write(convertedFile)
+}
+
+// names a converted file after the original and the orthography, e.g. story.md becomes story-listuguj.md
+func ConvertedFileName(fileName string, toOrthography string) string {
+	fileName = path.Base(strings.ReplaceAll(fileName, "\\", "/")) // browsers on windows can send the whole path
+	extension := path.Ext(fileName)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(fileName, extension), toOrthography, extension)
+}
@@ -0,0 +1,54 @@
+// tests for converting documents: only the text people read is converted, and markup, code and timestamps are left as they are
+
+package converter
+
+import "testing"
+
+// every case is converted from francis-smith to listuguj
+func TestConvertDocument(t *testing.T) {
+	documentCases := []struct {
+		name     string
+		format   string
+		input    string
+		expected string
+	}{
+		// markdown
+		{"markdown text", "md", "kesalk\n", "gesalg\n"},
+		{"markdown code span", "md", "kesalk `kesalk` kesalk", "gesalg `kesalk` gesalg"},
+		{"markdown double backtick code span", "md", "``kesalk ` kesalk`` kesalk", "``kesalk ` kesalk`` gesalg"},
+		{"markdown fenced code block", "md", "kesalk\n```\nkesalk\n```\nkesalk\n", "gesalg\n```\nkesalk\n```\ngesalg\n"},
+		{"markdown tilde fence", "md", "~~~go\nkesalk\n~~~\nkesalk", "~~~go\nkesalk\n~~~\ngesalg"},
+		{"markdown link address", "md", "[kesalk](http://kesalk.ca)", "[gesalg](http://kesalk.ca)"},
+		{"markdown autolink", "md", "<http://kesalk.ca> kesalk", "<http://kesalk.ca> gesalg"},
+		{"markdown reference definition", "md", "[kesalk]: http://kesalk.ca\n", "[kesalk]: http://kesalk.ca\n"},
+		{"markdown emphasis", "md", "*kesalk* __kesalk__", "*gesalg* __gesalg__"},
+		{"markdown schwa between letters", "md", "nest*k", "nest'g"},
+		{"markdown schwa inside emphasis", "md", "*nest*k*", "*nest'g*"},
+		// html
+		{"html text", "html", "<p>kesalk</p>", "<p>gesalg</p>"},
+		{"html attributes", "html", `<a href="kesalk.html" title="kesalk">kesalk</a>`, `<a href="kesalk.html" title="kesalk">gesalg</a>`},
+		{"html entity", "html", "kesalk &amp; kesalk&#233;", "gesalg &amp; gesalg&#233;"},
+		{"html comment", "html", "<!-- kesalk -->kesalk", "<!-- kesalk -->gesalg"},
+		{"html script", "html", "<script>var kesalk = 1;</script>kesalk", "<script>var kesalk = 1;</script>gesalg"},
+		{"html style", "html", "<STYLE>.kesalk {}</STYLE>kesalk", "<STYLE>.kesalk {}</STYLE>gesalg"},
+		{"html code", "html", "<code>kesalk</code> kesalk", "<code>kesalk</code> gesalg"},
+		{"html less than", "html", "1 < 2 kesalk", "1 < 2 gesalg"},
+		// subtitles
+		{"srt cue", "srt", "1\n00:00:01,000 --> 00:00:02,000\nkesalk\n\n2\n00:00:03,000 --> 00:00:04,000\nkesalk\n",
+			"1\n00:00:01,000 --> 00:00:02,000\ngesalg\n\n2\n00:00:03,000 --> 00:00:04,000\ngesalg\n"},
+		{"vtt header and cue settings", "vtt", "WEBVTT kesalk\n\n00:01.000 --> 00:02.000 align:start\n<i>kesalk</i>\n",
+			"WEBVTT kesalk\n\n00:01.000 --> 00:02.000 align:start\n<i>gesalg</i>\n"},
+		{"vtt note", "vtt", "WEBVTT\n\nNOTE kesalk\nkesalk\n\n00:01.000 --> 00:02.000\nkesalk\n",
+			"WEBVTT\n\nNOTE kesalk\nkesalk\n\n00:01.000 --> 00:02.000\ngesalg\n"},
+		{"vtt style", "vtt", "WEBVTT\n\nSTYLE\n::cue(.kesalk) {}\n\nkesalk\n00:01.000 --> 00:02.000\nkesalk\n",
+			"WEBVTT\n\nSTYLE\n::cue(.kesalk) {}\n\nkesalk\n00:01.000 --> 00:02.000\ngesalg\n"},
+	}
+	for _, Case := range documentCases {
+		outputStr, convertErr := ConvertDocument(Case.input, Case.format, "francissmith", "listuguj")
+		if convertErr != nil {
+			t.Errorf("%s: %v", Case.name, convertErr)
+		} else if outputStr != Case.expected {
+			t.Errorf("%s: ConvertDocument(%q) = %q, want %q", Case.name, Case.input, outputStr, Case.expected)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// the errors returned for input that cannot be converted, so that programs can tell them apart with errors.Is,
+// and the pages can say what is wrong instead of failing
+// text is checked with ValidateText before it is converted: it has to have something in it, be no longer than MaxInputLength,
+// and have no control characters or bytes that are not utf-8. ValidateWord also checks that a single word, e.g. a verb to conjugate,
+// only has the letters its orthography is written with
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrEmptyInput         = errors.New("empty input")
+	ErrInputTooLong       = errors.New("input too long")
+	ErrInvalidCharacter   = errors.New("invalid character")
+	ErrUnknownOrthography = errors.New("orthography type missing")
+)
+
+// the most characters that are converted at once, and the longest a single word can be
+const (
+	MaxInputLength = 100000
+	MaxWordLength  = 64
+)
+
+type InputError struct { // where the input is wrong, for ErrInputTooLong and ErrInvalidCharacter
+	Err         error  // one of the errors above, which errors.Is finds
+	Character   rune   // the character that is not allowed
+	Position    int    // where it is, in characters counting from 1
+	Length      int    // how long the input is, in characters
+	Orthography string // the orthography the word was checked against, if it was
+}
+
+func (Err *InputError) Error() string {
+	switch {
+	case errors.Is(Err.Err, ErrInputTooLong):
+		return fmt.Sprintf("%v: %d characters, the most is %d", Err.Err, Err.Length, Err.maxLength())
+	case Err.Orthography != "":
+		return fmt.Sprintf("%v: %q at character %d is not written in %s", Err.Err, Err.Character, Err.Position, OrthographyNames[Err.Orthography])
+	}
+	return fmt.Sprintf("%v: %q at character %d", Err.Err, Err.Character, Err.Position)
+}
+
+func (Err *InputError) Unwrap() error {
+	return Err.Err
+}
+
+// returns the length limit that was exceeded: words are checked against an orthography, texts are not
+func (Err *InputError) maxLength() int {
+	if Err.Orthography != "" {
+		return MaxWordLength
+	}
+	return MaxInputLength
+}
+
+// ValidateText returns an error if a text cannot be converted: ErrEmptyInput if it is empty or only whitespace,
+// or an *InputError for ErrInputTooLong, or for ErrInvalidCharacter if it has a control character or is not valid utf-8
+func ValidateText(inputStr string) error {
+	if strings.TrimSpace(inputStr) == "" {
+		return ErrEmptyInput
+	}
+	if length := utf8.RuneCountInString(inputStr); length > MaxInputLength {
+		return &InputError{Err: ErrInputTooLong, Length: length}
+	}
+	position := 0
+	for charIndex, char := range inputStr {
+		position++
+		if char == utf8.RuneError && !strings.HasPrefix(inputStr[charIndex:], string(utf8.RuneError)) { // a byte that is not utf-8, rather than a real �
+			return &InputError{Err: ErrInvalidCharacter, Character: char, Position: position}
+		}
+		if unicode.IsControl(char) && !strings.ContainsRune("\t\n\r", char) {
+			return &InputError{Err: ErrInvalidCharacter, Character: char, Position: position}
+		}
+	}
+	return nil
+}
+
+// ValidateWord returns an error if a single word cannot be read in an orthography: ErrEmptyInput, ErrUnknownOrthography,
+// or an *InputError for ErrInputTooLong (more than MaxWordLength characters), or for ErrInvalidCharacter if it has a character
+// that is not a letter of the orthography, e.g. a space, a digit, or ŭ in Francis-Smith
+// the word is checked after its diacritics are composed and its typing substitutions made, as it would be converted
+func ValidateWord(inputStr string, orthographyChoice string) error {
+	if strings.TrimSpace(inputStr) == "" {
+		return ErrEmptyInput
+	}
+	Definition, found := OrthographyDefinitions[orthographyChoice]
+	if !found || Definition.Shared {
+		return ErrUnknownOrthography
+	}
+	inputStr = Substitute(NormalizeUnicode(strings.ToLower(strings.TrimSpace(inputStr))), orthographyChoice)
+	if length := utf8.RuneCountInString(inputStr); length > MaxWordLength {
+		return &InputError{Err: ErrInputTooLong, Length: length, Orthography: orthographyChoice}
+	}
+	letters := Definition.Letters()
+	position := 0
+	for _, char := range inputStr {
+		position++
+		if !letters[char] {
+			return &InputError{Err: ErrInvalidCharacter, Character: char, Position: position, Orthography: orthographyChoice}
+		}
+	}
+	return nil
+}
+
+// Letters returns every character a word in the orthography can have: the letters a to z, the apostrophe,
+// and every character its own rules read or write (not the rules of shared rule sets, which work on unified orthography),
+// its examples, and the substitutions that can be typed in it
+func (Definition *Orthography) Letters() map[rune]bool {
+	letters := make(map[rune]bool)
+	for char := 'a'; char <= 'z'; char++ {
+		letters[char] = true
+	}
+	letters['\''] = true
+	Definition.addLetters(letters, 0)
+	for _, ThisSubstitution := range SubstitutionsFor(Definition.Name) {
+		for _, char := range ThisSubstitution.From + ThisSubstitution.To {
+			letters[char] = true
+		}
+	}
+	return letters
+}
+
+// adds the characters of an orthography's rules and examples, and those of the orthographies it includes that are not shared
+func (Definition *Orthography) addLetters(letters map[rune]bool, depth int) {
+	for _, example := range Definition.NormalizeExamples {
+		for _, char := range example[0] {
+			letters[char] = true
+		}
+	}
+	for _, example := range Definition.EncodeExamples {
+		for _, char := range example[1] {
+			letters[char] = true
+		}
+	}
+	for _, RuleStep := range Definition.Normalize {
+		for _, RuleEntry := range RuleStep.Rules {
+			Definition.addRuleLetters(letters, RuleEntry, RuleEntry.From, depth) // what the orthography reads
+		}
+	}
+	for _, RuleStep := range Definition.Encode {
+		for _, RuleEntry := range RuleStep.Rules {
+			Definition.addRuleLetters(letters, RuleEntry, RuleEntry.To, depth) // what it writes
+		}
+	}
+}
+
+// adds the characters of one side of a rule, or of the orthography it includes
+func (Definition *Orthography) addRuleLetters(letters map[rune]bool, RuleEntry Rule, characters string, depth int) {
+	if RuleEntry.Include == "" {
+		for _, char := range characters {
+			letters[char] = true
+		}
+		return
+	}
+	IncludedDefinition, found := OrthographyDefinitions[RuleEntry.Include]
+	if found && !IncludedDefinition.Shared && depth < maxIncludeDepth {
+		IncludedDefinition.addLetters(letters, depth+1)
+	}
+}
+
+// ErrorCode returns a short name for the kind of an error, for programs using the api:
+// "empty_input", "input_too_long", "invalid_character", "unknown_orthography", "unbalanced_escape", or "invalid_request" for anything else
+func ErrorCode(err error) string {
+	var EscapeErr *EscapeError
+	switch {
+	case errors.Is(err, ErrEmptyInput):
+		return "empty_input"
+	case errors.Is(err, ErrInputTooLong):
+		return "input_too_long"
+	case errors.Is(err, ErrInvalidCharacter):
+		return "invalid_character"
+	case errors.Is(err, ErrUnknownOrthography):
+		return "unknown_orthography"
+	case errors.As(err, &EscapeErr):
+		return "unbalanced_escape"
+	}
+	return "invalid_request"
+}
+
+// returns what the converter page shows for an error, in english and french like the rest of the page
+func pageErrorMessage(err error) string {
+	var InputErr *InputError
+	errors.As(err, &InputErr)
+	switch {
+	case errors.Is(err, ErrEmptyInput):
+		return "Enter some text to convert. | Saisis un texte à convertir."
+	case errors.Is(err, ErrInputTooLong) && InputErr != nil:
+		return fmt.Sprintf("The text is too long: %d characters, the most is %d. | Le texte est trop long : %d caractères, le maximum est %d.",
+			InputErr.Length, MaxInputLength, InputErr.Length, MaxInputLength)
+	case errors.Is(err, ErrInvalidCharacter) && InputErr != nil:
+		return fmt.Sprintf("The text has a character that is not text (%U) at character %d. | Le texte contient un caractère qui n'est pas du texte (%U) au caractère %d.",
+			InputErr.Character, InputErr.Position, InputErr.Character, InputErr.Position)
+	case errors.Is(err, ErrUnknownOrthography):
+		return "Choose the orthography the text is in. | Choisis l'orthographe du texte."
+	}
+	return err.Error()
+}
@@ -0,0 +1,208 @@
+// finds escaped sequences: text between { and } that is left exactly as it is, e.g. names and dates in "Wejia'p {Ontario}ek"
+// escaped sequences can span spaces, punctuation and line breaks, and can be nested: only the outermost markers are removed,
+// so "{a {b} c}" is written a {b} c. a marker with a backslash before it (\{ or \}) is a literal brace, in or out of an escaped sequence
+// the markers can be changed, e.g. to [[ and ]] for text that has braces in it, and a marker that is never closed, or closes nothing, is an error
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type EscapeMarkers struct { // the markers around escaped sequences; the zero value is { and }
+	Open  string
+	Close string
+}
+
+// the markers used when none are given
+var DefaultEscapeMarkers = EscapeMarkers{"{", "}"}
+
+// the character that makes the marker after it literal
+const escapeLiteral = `\`
+
+type EscapeError struct { // a marker that is never closed, or a closing marker that closes nothing
+	Marker   string
+	Unclosed bool
+	Line     int    // where the marker is, counting from 1
+	Column   int    // in characters
+	Context  string // the text around the marker
+}
+
+func (Err *EscapeError) Error() string {
+	problem := "closes no escaped sequence"
+	if Err.Unclosed {
+		problem = "is never closed"
+	}
+	return fmt.Sprintf("unbalanced escape: %q at line %d, column %d %s (near %q); write %s%s for a literal %s",
+		Err.Marker, Err.Line, Err.Column, problem, Err.Context, escapeLiteral, Err.Marker, Err.Marker)
+}
+
+// returns the markers to use, and an error if they cannot be used
+func (Markers EscapeMarkers) orDefault() (EscapeMarkers, error) {
+	if Markers.Open == "" && Markers.Close == "" {
+		return DefaultEscapeMarkers, nil
+	}
+	if Markers.Open == "" || Markers.Close == "" {
+		return Markers, errors.New("escape markers need both an opening and a closing marker")
+	}
+	for _, marker := range []string{Markers.Open, Markers.Close} {
+		if strings.Contains(marker, escapeLiteral) || strings.ContainsAny(marker, " \t\r\n") {
+			return Markers, fmt.Errorf("escape marker %q cannot contain spaces or %s", marker, escapeLiteral)
+		}
+	}
+	return Markers, nil
+}
+
+type escapeScanner struct { // splits text into escaped sequences and the rest, keeping its place between pieces of the same document
+	Markers     EscapeMarkers
+	Depth       int // how many escaped sequences the scanner is in
+	Line        int // where the scanner is
+	Column      int
+	OpenedError *EscapeError // where the outermost open escaped sequence began, in case it is never closed
+	KeepMarkers bool         // if the markers stay in the pieces, for showing text as it was typed
+}
+
+func newEscapeScanner(Markers EscapeMarkers) (*escapeScanner, error) {
+	Markers, markersErr := Markers.orDefault()
+	if markersErr != nil {
+		return nil, markersErr
+	}
+	return &escapeScanner{Markers: Markers, Line: 1, Column: 1}, nil
+}
+
+// splits a piece of text into escaped sequences (Escaped) and the text around them (not yet split into words), without the markers
+// text after an escaped sequence that was opened in an earlier piece and not closed is escaped up to its closing marker
+// a closing marker that closes nothing is kept as text, and returned as an error
+func (Scanner *escapeScanner) split(inputStr string) ([]ConversionString, error) {
+	var Pieces []ConversionString
+	var currentPiece strings.Builder
+	var firstErr error
+	addPiece := func() {
+		if currentPiece.Len() > 0 {
+			Pieces = append(Pieces, ConversionString{InputString: currentPiece.String(), Escaped: Scanner.Depth > 0})
+			currentPiece.Reset()
+		}
+	}
+	Open, Close := Scanner.Markers.Open, Scanner.Markers.Close
+	for charIndex := 0; charIndex < len(inputStr); {
+		rest := inputStr[charIndex:]
+		switch {
+		case strings.HasPrefix(rest, escapeLiteral+Open):
+			currentPiece.WriteString(Scanner.marker(escapeLiteral, Open))
+			charIndex += Scanner.advance(rest[:len(escapeLiteral)+len(Open)])
+		case strings.HasPrefix(rest, escapeLiteral+Close):
+			currentPiece.WriteString(Scanner.marker(escapeLiteral, Close))
+			charIndex += Scanner.advance(rest[:len(escapeLiteral)+len(Close)])
+		case Scanner.Depth > 0 && strings.HasPrefix(rest, Close): // checked before Open, so that markers that are the same (e.g. ") open and close
+			if Scanner.Depth > 1 {
+				currentPiece.WriteString(Close)
+			} else {
+				currentPiece.WriteString(Scanner.marker("", Close))
+				addPiece()
+			}
+			Scanner.Depth--
+			charIndex += Scanner.advance(Close)
+		case strings.HasPrefix(rest, Open):
+			if Scanner.Depth == 0 {
+				addPiece()
+				Scanner.OpenedError = Scanner.errorAt(inputStr, charIndex, true)
+				currentPiece.WriteString(Scanner.marker("", Open))
+			} else {
+				currentPiece.WriteString(Open)
+			}
+			Scanner.Depth++
+			charIndex += Scanner.advance(Open)
+		case strings.HasPrefix(rest, Close):
+			if firstErr == nil {
+				firstErr = Scanner.errorAt(inputStr, charIndex, false)
+			}
+			currentPiece.WriteString(Close)
+			charIndex += Scanner.advance(Close)
+		default:
+			_, charLength := utf8.DecodeRuneInString(rest)
+			currentPiece.WriteString(rest[:charLength])
+			charIndex += Scanner.advance(rest[:charLength])
+		}
+	}
+	addPiece()
+	return Pieces, firstErr
+}
+
+// returns a marker as it is written in a piece: only the marker itself, or nothing at all unless the scanner keeps them
+func (Scanner *escapeScanner) marker(literal string, Marker string) string {
+	if Scanner.KeepMarkers {
+		return literal + Marker
+	}
+	if literal != "" {
+		return Marker
+	}
+	return ""
+}
+
+// moves the scanner's place past some text, and returns its length in bytes
+func (Scanner *escapeScanner) advance(inputStr string) int {
+	for _, char := range inputStr {
+		if char == '\n' {
+			Scanner.Line++
+			Scanner.Column = 1
+		} else {
+			Scanner.Column++
+		}
+	}
+	return len(inputStr)
+}
+
+// returns an error for the marker at charIndex, at the scanner's place
+func (Scanner *escapeScanner) errorAt(inputStr string, charIndex int, unclosed bool) *EscapeError {
+	Marker := Scanner.Markers.Close
+	if unclosed {
+		Marker = Scanner.Markers.Open
+	}
+	contextStart := max(0, charIndex-20)
+	for contextStart > 0 && !utf8.RuneStart(inputStr[contextStart]) {
+		contextStart--
+	}
+	contextEnd := min(len(inputStr), charIndex+len(Marker)+20)
+	for contextEnd < len(inputStr) && !utf8.RuneStart(inputStr[contextEnd]) {
+		contextEnd++
+	}
+	return &EscapeError{Marker, unclosed, Scanner.Line, Scanner.Column, strings.TrimSpace(inputStr[contextStart:contextEnd])}
+}
+
+// returns an error if an escaped sequence is still open at the end of the text
+func (Scanner *escapeScanner) finish() error {
+	if Scanner.Depth > 0 {
+		return Scanner.OpenedError
+	}
+	return nil
+}
+
+// splits a piece of text into escaped sequences, and words and punctuation (see tokenizeWords and Protection.tokenize)
+func (Scanner *escapeScanner) tokenize(inputStr string, orthographyChoice string, Protect *Protection) ([]ConversionString, error) {
+	var Tokens []ConversionString
+	Pieces, splitErr := Scanner.split(inputStr)
+	for _, Piece := range Pieces {
+		if Piece.Escaped {
+			Tokens = append(Tokens, Piece)
+		} else {
+			Tokens = append(Tokens, Protect.tokenize(Piece.InputString, orthographyChoice)...)
+		}
+	}
+	return Tokens, splitErr
+}
+
+// splits a whole text into escaped sequences and the text around them, as split does
+func splitEscapedSequences(inputStr string, Markers EscapeMarkers) ([]ConversionString, error) {
+	Scanner, markersErr := newEscapeScanner(Markers)
+	if markersErr != nil {
+		return nil, markersErr
+	}
+	Pieces, splitErr := Scanner.split(inputStr)
+	if splitErr != nil {
+		return Pieces, splitErr
+	}
+	return Pieces, Scanner.finish()
+}
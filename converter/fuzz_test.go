@@ -0,0 +1,111 @@
+// fuzz tests for the converter: normalizing words, encoding unified orthography, and converting whole texts
+// the seeds are the examples in the orthography files and every sequence in the substitution table,
+// so the fuzzer starts from words that reach the rules of every orthography
+// go test runs the seeds; go test -fuzz=FuzzConvertAll ./converter runs the fuzzer
+
+package converter
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+// text that has been hard to split or convert: escaped sequences, capitals, lookalike characters, and punctuation inside words
+var oddTexts = []string{
+	"", " ", "'", "''", "*", "{", "}", "\\{", "{a {b} c}", "Wejia'p {Ontario}ek", "KESALK", "Mu/n'chu/ A/la",
+	"ewiʼkiket", "ı̵", "pèsik", "e'-", "a'.", "https://example.com", "3 kesalk", "\x00", "\xff",
+}
+
+// a word from every example of every orthography, and every sequence that can be typed in it
+func addWordSeeds(f *testing.F) {
+	for orthographyIndex, orthography := range Orthographies {
+		for _, example := range OrthographyDefinitions[orthography].NormalizeExamples {
+			f.Add(example[0], uint8(orthographyIndex))
+		}
+		for _, ThisSubstitution := range SubstitutionsFor(orthography) {
+			f.Add("p"+ThisSubstitution.From+"t", uint8(orthographyIndex))
+		}
+	}
+	for _, text := range oddTexts {
+		f.Add(text, uint8(0))
+	}
+}
+
+// the orthography a fuzzed number stands for
+func fuzzedOrthography(orthographyIndex uint8) string {
+	return Orthographies[int(orthographyIndex)%len(Orthographies)]
+}
+
+// NormalizeUnicode never changes its own output
+func FuzzNormalizeUnicode(f *testing.F) {
+	addWordSeeds(f)
+	f.Fuzz(func(t *testing.T, inputStr string, _ uint8) {
+		normalizedStr := NormalizeUnicode(inputStr)
+		if again := NormalizeUnicode(normalizedStr); again != normalizedStr {
+			t.Errorf("NormalizeUnicode(%q) = %q, but normalizing that again gives %q", inputStr, normalizedStr, again)
+		}
+	})
+}
+
+// NormalizeWord never panics, and gives the same unified word for a word however its diacritics and apostrophes were typed
+func FuzzNormalizeWord(f *testing.F) {
+	addWordSeeds(f)
+	f.Fuzz(func(t *testing.T, inputStr string, orthographyIndex uint8) {
+		orthography := fuzzedOrthography(orthographyIndex)
+		unifiedString, normalizeErr := NormalizeWord(inputStr, orthography)
+		if normalizeErr != nil {
+			t.Fatalf("NormalizeWord(%q, %q): %v", inputStr, orthography, normalizeErr)
+		}
+		if utf8.ValidString(inputStr) && !utf8.ValidString(unifiedString) {
+			t.Errorf("NormalizeWord(%q, %q) = %q, which is not utf-8", inputStr, orthography, unifiedString)
+		}
+		if equivalentString, _ := NormalizeWord(NormalizeUnicode(inputStr), orthography); equivalentString != unifiedString {
+			t.Errorf("NormalizeWord(%q, %q) = %q, but the same word normalized gives %q", inputStr, orthography, unifiedString, equivalentString)
+		}
+	})
+}
+
+// encoding unified orthography never panics, in any orthography or split into syllables
+func FuzzEncode(f *testing.F) {
+	for orthographyIndex, orthography := range Orthographies {
+		for _, example := range OrthographyDefinitions[orthography].EncodeExamples {
+			f.Add(example[0], uint8(orthographyIndex))
+		}
+	}
+	f.Add("6789+0", uint8(0))
+	f.Add("*@3!%&$#=", uint8(0))
+	f.Fuzz(func(t *testing.T, unifiedString string, orthographyIndex uint8) {
+		orthography := fuzzedOrthography(orthographyIndex)
+		encodedString := OrthographyDefinitions[orthography].EncodeString(unifiedString)
+		if utf8.ValidString(unifiedString) && !utf8.ValidString(encodedString) {
+			t.Errorf("EncodeString(%q) in %q = %q, which is not utf-8", unifiedString, orthography, encodedString)
+		}
+		OrthographyDefinitions[orthography].EncodeSyllables(Syllabify(unifiedString))
+	})
+}
+
+// converting text never panics, and every error it returns is one of the converter's own
+func FuzzConvertAll(f *testing.F) {
+	addWordSeeds(f)
+	f.Fuzz(func(t *testing.T, inputStr string, orthographyIndex uint8) {
+		orthography := fuzzedOrthography(orthographyIndex)
+		if orthographyIndex%7 == 0 {
+			orthography = "auto"
+		}
+		for _, Options := range []ConvertOptions{{}, {Syllables: true}, {Protect: NewProtection("", "")}} {
+			OutputWords, convertErr := ConvertAllOptions(inputStr, orthography, Options)
+			if convertErr != nil {
+				if ErrorCode(convertErr) == "invalid_request" {
+					t.Errorf("ConvertAllOptions(%q, %q, %+v) returned an error of no known kind: %v", inputStr, orthography, Options, convertErr)
+				}
+				continue
+			}
+			for target, form := range OutputWords.Forms {
+				if utf8.ValidString(inputStr) && !utf8.ValidString(form) {
+					t.Errorf("ConvertAllOptions(%q, %q, %+v) in %q = %q, which is not utf-8", inputStr, orthography, Options, target, form)
+				}
+			}
+		}
+		PreviewSubstitutions(inputStr, orthography)
+	})
+}
@@ -0,0 +1,291 @@
+// converts word processor documents: .docx (office open xml) and .odt (opendocument text)
+// both are zip files of xml. only the text of paragraphs is changed, byte for byte in place,
+// so styles, formatting, images, and everything else in the file stay exactly as they were
+// text is converted one run at a time (a run is a piece of text with the same formatting). a word that is split between runs,
+// e.g. by a spelling mark or a change of formatting halfway through, is moved into the run it begins in before it is converted,
+// and an escaped sequence can begin in one run and end in a later one
+
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+type officeFormat struct { // where the text is in one kind of word processor document
+	Parts       *regexp.Regexp  // the xml files in the zip that hold text
+	Namespace   string          // the namespace of the elements below
+	Paragraphs  map[string]bool // elements that hold a paragraph of text
+	TextParents map[string]bool // elements whose text is converted
+	Breaks      map[string]bool // elements inside a paragraph that separate words, e.g. tabs and line breaks
+}
+
+type officeRun struct { // the text of one text element in a part
+	Text       string
+	Start      int // the bytes of the escaped text in the part
+	End        int
+	TagStart   int // the bytes of the start tag of the text element, in case it needs xml:space="preserve"
+	TagEnd     int
+	Preserved  bool // if the element already keeps its spaces
+	Paragraph  int  // the paragraph the run is in
+	BreakAfter bool // if a break, or the start of a nested paragraph, comes after the run
+}
+
+// escapes converted text for xml. apostrophes are left as they are, as word processors write them
+var officeTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+var officeFormats = map[string]officeFormat{
+	"docx": {
+		Parts:       regexp.MustCompile(`^word/(document|header[0-9]*|footer[0-9]*|footnotes|endnotes|comments)\.xml$`),
+		Namespace:   "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
+		Paragraphs:  map[string]bool{"p": true},
+		TextParents: map[string]bool{"t": true},
+		Breaks:      map[string]bool{"tab": true, "br": true, "cr": true, "ptab": true},
+	},
+	"odt": {
+		Parts:       regexp.MustCompile(`^(content|styles)\.xml$`),
+		Namespace:   "urn:oasis:names:tc:opendocument:xmlns:text:1.0",
+		Paragraphs:  map[string]bool{"p": true, "h": true},
+		TextParents: map[string]bool{"p": true, "h": true, "span": true, "a": true},
+		Breaks:      map[string]bool{"s": true, "tab": true, "line-break": true},
+	},
+}
+
+// ConvertOfficeDocument converts the text of a .docx or .odt file (format "docx" or "odt") from one orthography to another,
+// and returns the converted file. fromOrthography may be "auto", to detect it from the text of the whole document.
+func ConvertOfficeDocument(contents []byte, format string, fromOrthography string, toOrthography string) ([]byte, error) {
+	Format, found := officeFormats[format]
+	if !found {
+		return nil, fmt.Errorf("unsupported document format %q", format)
+	}
+	if _, found := OrthographyNames[toOrthography]; !found {
+		return nil, ErrUnknownOrthography
+	}
+	zipReader, zipErr := zip.NewReader(bytes.NewReader(contents), int64(len(contents)))
+	if zipErr != nil {
+		return nil, fmt.Errorf("the %s file could not be read: %v", format, zipErr)
+	}
+
+	parts := make(map[string][]byte) // the contents of every part with text
+	partRuns := make(map[string][]officeRun)
+	var allText strings.Builder // for detecting the orthography
+	uncompressedLeft := int64(MaxUncompressedDocumentSize)
+	for _, zipFile := range zipReader.File {
+		if !Format.Parts.MatchString(zipFile.Name) {
+			continue
+		}
+		partContents, readErr := readZipFile(zipFile, uncompressedLeft)
+		if readErr != nil {
+			return nil, readErr
+		}
+		uncompressedLeft -= int64(len(partContents))
+		Runs, parseErr := Format.findRuns(partContents)
+		if parseErr != nil {
+			return nil, fmt.Errorf("%s: %v", zipFile.Name, parseErr)
+		}
+		parts[zipFile.Name] = partContents
+		partRuns[zipFile.Name] = joinSplitWords(Runs)
+		for _, Run := range Runs {
+			allText.WriteString(Run.Text)
+			allText.WriteString(" ")
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("the %s file has no text", format)
+	}
+	if fromOrthography == "auto" {
+		fromOrthography = DetectOrthography(allText.String()).Orthography
+	}
+	if _, found := OrthographyNames[fromOrthography]; !found {
+		return nil, ErrUnknownOrthography
+	}
+
+	var outputBuffer bytes.Buffer
+	zipWriter := zip.NewWriter(&outputBuffer)
+	for _, zipFile := range zipReader.File {
+		Runs, found := partRuns[zipFile.Name]
+		if !found { // everything else is copied as it is, compressed the same way (the odt mimetype has to stay uncompressed and first)
+			copyErr := zipWriter.Copy(zipFile)
+			if copyErr != nil {
+				return nil, copyErr
+			}
+			continue
+		}
+		convertedPart, convertErr := convertRuns(parts[zipFile.Name], Runs, fromOrthography, toOrthography)
+		if convertErr != nil {
+			return nil, fmt.Errorf("%s: %w", zipFile.Name, convertErr)
+		}
+		Header := zipFile.FileHeader
+		partWriter, createErr := zipWriter.CreateHeader(&Header)
+		if createErr != nil {
+			return nil, createErr
+		}
+		_, writeErr := partWriter.Write(convertedPart)
+		if writeErr != nil {
+			return nil, writeErr
+		}
+	}
+	closeErr := zipWriter.Close()
+	if closeErr != nil {
+		return nil, closeErr
+	}
+	return outputBuffer.Bytes(), nil
+}
+
+// reads a whole file from a zip, or returns ErrDocumentTooLarge if it is more than limit bytes uncompressed
+// the size in the header is checked first, but it can be wrong, so no more than limit bytes are ever read
+func readZipFile(zipFile *zip.File, limit int64) ([]byte, error) {
+	if zipFile.UncompressedSize64 > uint64(limit) {
+		return nil, fmt.Errorf("%s: %w", zipFile.Name, ErrDocumentTooLarge)
+	}
+	fileReader, openErr := zipFile.Open()
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer fileReader.Close()
+	fileContents, readErr := io.ReadAll(io.LimitReader(fileReader, limit+1))
+	if readErr != nil {
+		return nil, readErr
+	}
+	if int64(len(fileContents)) > limit {
+		return nil, fmt.Errorf("%s: %w", zipFile.Name, ErrDocumentTooLarge)
+	}
+	return fileContents, nil
+}
+
+// finds the text in a part, and where it is
+func (Format officeFormat) findRuns(partContents []byte) ([]officeRun, error) {
+	var Runs []officeRun
+	var elementStack []xml.StartElement
+	var paragraphStack []int // the paragraphs the decoder is in, innermost last
+	paragraphCount := 0
+	var lastTag [2]int // the bytes of the last start tag
+	decoder := xml.NewDecoder(bytes.NewReader(partContents))
+	for {
+		tokenStart := int(decoder.InputOffset())
+		token, tokenErr := decoder.Token()
+		if tokenErr == io.EOF {
+			break
+		}
+		if tokenErr != nil {
+			return nil, tokenErr
+		}
+		tokenEnd := int(decoder.InputOffset())
+		switch typedToken := token.(type) {
+		case xml.StartElement:
+			elementStack = append(elementStack, typedToken)
+			lastTag = [2]int{tokenStart, tokenEnd}
+			if typedToken.Name.Space != Format.Namespace {
+				continue
+			}
+			if Format.Paragraphs[typedToken.Name.Local] || Format.Breaks[typedToken.Name.Local] {
+				if len(Runs) > 0 { // a nested paragraph (a footnote or text box) splits the words of the one around it
+					Runs[len(Runs)-1].BreakAfter = true
+				}
+			}
+			if Format.Paragraphs[typedToken.Name.Local] {
+				paragraphCount++
+				paragraphStack = append(paragraphStack, paragraphCount)
+			}
+		case xml.EndElement:
+			elementStack = elementStack[:len(elementStack)-1]
+			if typedToken.Name.Space == Format.Namespace && Format.Paragraphs[typedToken.Name.Local] {
+				paragraphStack = paragraphStack[:len(paragraphStack)-1]
+				if len(Runs) > 0 {
+					Runs[len(Runs)-1].BreakAfter = true
+				}
+			}
+		case xml.CharData:
+			if len(elementStack) == 0 || len(paragraphStack) == 0 {
+				continue
+			}
+			parent := elementStack[len(elementStack)-1]
+			if parent.Name.Space != Format.Namespace || !Format.TextParents[parent.Name.Local] {
+				continue
+			}
+			var Run officeRun
+			Run.Text = string(typedToken)
+			Run.Start, Run.End = tokenStart, tokenEnd
+			Run.TagStart, Run.TagEnd = lastTag[0], lastTag[1]
+			for _, attribute := range parent.Attr {
+				if attribute.Name.Local == "space" && attribute.Value == "preserve" {
+					Run.Preserved = true
+				}
+			}
+			Run.Paragraph = paragraphStack[len(paragraphStack)-1]
+			if len(Runs) > 0 && Runs[len(Runs)-1].End == Run.Start && Runs[len(Runs)-1].Paragraph == Run.Paragraph {
+				Runs[len(Runs)-1].Text += Run.Text // text around a comment or cdata section comes in pieces
+				Runs[len(Runs)-1].End = Run.End
+				continue
+			}
+			Runs = append(Runs, Run)
+		}
+	}
+	return Runs, nil
+}
+
+// moves the rest of any word that goes on into the next runs of the same paragraph into the run it begins in
+func joinSplitWords(Runs []officeRun) []officeRun {
+	for runIndex := range Runs {
+		for nextIndex := runIndex + 1; nextIndex < len(Runs); nextIndex++ {
+			if Runs[nextIndex-1].BreakAfter || Runs[nextIndex].Paragraph != Runs[runIndex].Paragraph {
+				break
+			}
+			movedLength := continuationLength(Runs[runIndex].Text, Runs[nextIndex].Text)
+			Runs[runIndex].Text += Runs[nextIndex].Text[:movedLength]
+			Runs[nextIndex].Text = Runs[nextIndex].Text[movedLength:]
+			if Runs[nextIndex].Text != "" || movedLength == 0 {
+				break
+			}
+		}
+	}
+	return Runs
+}
+
+// returns how many bytes at the start of the next text belong to the word at the end of the text before it
+func continuationLength(previousText string, nextText string) int {
+	if !endsWithLetter(previousText) {
+		return 0
+	}
+	combinedRunes := []rune(previousText + nextText)
+	charIndex := len([]rune(previousText))
+	movedLength := 0
+	for charIndex < len(combinedRunes) && isWordCharacter(combinedRunes, charIndex, true, false, "") {
+		movedLength += len(string(combinedRunes[charIndex]))
+		charIndex++
+	}
+	return movedLength
+}
+
+// writes a part with the text of every run converted
+func convertRuns(partContents []byte, Runs []officeRun, fromOrthography string, toOrthography string) ([]byte, error) {
+	var outputBuffer bytes.Buffer
+	lastEnd := 0
+	Scanner, _ := newEscapeScanner(DefaultEscapeMarkers)
+	for _, Run := range Runs {
+		convertedStr := Run.Text
+		if strings.TrimSpace(Run.Text) != "" {
+			var convertErr error
+			convertedStr, convertErr = Scanner.convert(Run.Text, fromOrthography, toOrthography)
+			if convertErr != nil {
+				return nil, convertErr
+			}
+		}
+		if !Run.Preserved && convertedStr != strings.TrimSpace(convertedStr) && bytes.HasSuffix(partContents[Run.TagStart:Run.TagEnd], []byte("<w:t>")) {
+			// a word moved out of this run can leave a space at its start, which word drops unless it is told to keep it
+			outputBuffer.Write(partContents[lastEnd:Run.TagStart])
+			outputBuffer.WriteString(`<w:t xml:space="preserve">`)
+			lastEnd = Run.TagEnd
+		}
+		outputBuffer.Write(partContents[lastEnd:Run.Start])
+		outputBuffer.WriteString(officeTextEscaper.Replace(convertedStr))
+		lastEnd = Run.End
+	}
+	outputBuffer.Write(partContents[lastEnd:])
+	return outputBuffer.Bytes(), Scanner.finish()
+}
@@ -0,0 +1,59 @@
+// tests for reading word processor documents
+
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// makes a .docx file whose document.xml is the given number of bytes of text uncompressed, and much smaller compressed
+func makeLargeDocx(t *testing.T, textSize int) []byte {
+	var docxBuffer bytes.Buffer
+	zipWriter := zip.NewWriter(&docxBuffer)
+	partWriter, createErr := zipWriter.Create("word/document.xml")
+	if createErr != nil {
+		t.Fatal(createErr)
+	}
+	partWriter.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>`))
+	partWriter.Write(bytes.Repeat([]byte("a"), textSize))
+	partWriter.Write([]byte(`</w:t></w:r></w:p></w:body></w:document>`))
+	closeErr := zipWriter.Close()
+	if closeErr != nil {
+		t.Fatal(closeErr)
+	}
+	return docxBuffer.Bytes()
+}
+
+// a document that is small compressed but too large uncompressed is not read, and neither is a part larger than its header says
+func TestConvertOfficeDocumentTooLarge(t *testing.T) {
+	docxContents := makeLargeDocx(t, MaxUncompressedDocumentSize)
+	if len(docxContents) > MaxDocumentSize {
+		t.Fatalf("the test document is %d bytes compressed, more than can be uploaded", len(docxContents))
+	}
+	_, convertErr := ConvertOfficeDocument(docxContents, "docx", "francissmith", "listuguj")
+	if !errors.Is(convertErr, ErrDocumentTooLarge) {
+		t.Errorf("got %v, want %v", convertErr, ErrDocumentTooLarge)
+	}
+
+	zipReader, zipErr := zip.NewReader(bytes.NewReader(docxContents), int64(len(docxContents)))
+	if zipErr != nil {
+		t.Fatal(zipErr)
+	}
+	zipFile := zipReader.File[0]
+	zipFile.UncompressedSize64 = 1 // a header that says the part is small: archive/zip stops reading where the header says it ends
+	_, readErr := readZipFile(zipFile, MaxUncompressedDocumentSize)
+	if readErr == nil {
+		t.Error("a part larger than its header says was read")
+	}
+}
+
+// a document of a sensible size is still converted
+func TestConvertOfficeDocumentSmall(t *testing.T) {
+	_, convertErr := ConvertOfficeDocument(makeLargeDocx(t, 1000), "docx", "francissmith", "listuguj")
+	if convertErr != nil {
+		t.Error(convertErr)
+	}
+}
@@ -0,0 +1,95 @@
+// keeps text that is not mi'kmaw as it is, without it having to be put in {}, when the user asks for it:
+// numbers and words with digits in them (2024, 3e, 9h30), links, e-mail addresses, and english and french words and place names from foreignwords.txt
+// a word the user needs converted anyway can be given in the convert list of a request, and any other word that should be kept in the keep list
+
+package converter
+
+import (
+	_ "embed"
+	"regexp"
+	"strings"
+)
+
+//go:embed foreignwords.txt
+var foreignWordsFile string
+
+// english and french words that are kept as they are, lowercase
+var ForeignWords = parseForeignWords(foreignWordsFile)
+
+type Protection struct { // which words are kept as they are, for one request
+	Convert map[string]bool // words that are always converted, even if they are in ForeignWords
+	Keep    map[string]bool // more words that are never converted
+}
+
+// links, e-mail addresses, and words with digits in them
+// a link ends before any punctuation after it, so that "(see www.mikmaq.ca.)" keeps its full stop and bracket
+var protectedPattern = regexp.MustCompile(`(?i)(?:https?://|ftp://|www\.)[^\s<>"{}]*[^\s<>"{}.,;:!?)\]'’]|[\p{L}\p{N}._%+-]+@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)+|[\p{L}\p{M}]*\p{N}[\p{L}\p{M}\p{N}]*`)
+
+// french words that lose their vowel before another word, e.g. l'école, c'est, qu'il
+var frenchElision = regexp.MustCompile(`^(?:c|d|j|l|m|n|s|t|qu|jusqu|lorsqu|puisqu)'`)
+
+// reads the words in foreignwords.txt
+func parseForeignWords(fileContents string) map[string]bool {
+	Words := make(map[string]bool)
+	for _, line := range strings.Split(fileContents, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line != "" && !strings.HasPrefix(line, "#") {
+			Words[line] = true
+		}
+	}
+	return Words
+}
+
+// NewProtection makes a Protection from two lists of words, separated by commas, spaces or line breaks, as they are typed on the page or given to the api
+func NewProtection(convertWords string, keepWords string) *Protection {
+	return &Protection{readWordList(convertWords), readWordList(keepWords)}
+}
+
+// splits a list of words typed by the user
+func readWordList(listStr string) map[string]bool {
+	Words := make(map[string]bool)
+	for _, word := range strings.Fields(strings.NewReplacer(",", " ", ";", " ").Replace(listStr)) {
+		Words[NormalizeUnicode(strings.ToLower(word))] = true
+	}
+	return Words
+}
+
+// returns true if a lowercase word is kept as it is
+func (Protect *Protection) keeps(word string) bool {
+	if Protect == nil || Protect.Convert[word] {
+		return false
+	}
+	if Protect.Keep[word] {
+		return true
+	}
+	if KnownWords.Contains(word) { // a mi'kmaw word that happens to be spelled like an english or french one
+		return false
+	}
+	return ForeignWords[word] || ForeignWords[frenchElision.ReplaceAllString(word, "")]
+}
+
+// splits a piece of text that is not escaped into words and punctuation like tokenizeWords,
+// escaping links, e-mail addresses, numbers, and the words Protect keeps. a nil Protection escapes nothing
+func (Protect *Protection) tokenize(inputStr string, orthographyChoice string) []ConversionString {
+	if Protect == nil {
+		return tokenizeWords(inputStr, orthographyChoice)
+	}
+	var Tokens []ConversionString
+	lastEnd := 0
+	for _, match := range protectedPattern.FindAllStringIndex(inputStr, -1) {
+		Tokens = append(Tokens, Protect.escapeWords(tokenizeWords(inputStr[lastEnd:match[0]], orthographyChoice))...)
+		Tokens = append(Tokens, ConversionString{InputString: inputStr[match[0]:match[1]], Escaped: true})
+		lastEnd = match[1]
+	}
+	return append(Tokens, Protect.escapeWords(tokenizeWords(inputStr[lastEnd:], orthographyChoice))...)
+}
+
+// escapes the words Protect keeps, as they were typed
+func (Protect *Protection) escapeWords(Tokens []ConversionString) []ConversionString {
+	for tokenIndex, Token := range Tokens {
+		if !Token.Punctuation && Protect.keeps(Token.InputString) {
+			Tokens[tokenIndex] = ConversionString{InputString: Token.TypedString, Escaped: true}
+		}
+	}
+	return Tokens
+}
@@ -0,0 +1,226 @@
+// property tests for converting between orthographies: a word written in one orthography, converted into another and back,
+// is written the same as it was, for every pair of orthographies that can be read back without guessing (those with no ambiguities),
+// or differs only in what one of them writes like something else (see Orthography.Losses)
+// the words are made up at random from the sounds of unified orthography, following where those sounds can be in a word,
+// and a word that fails is made as short as it can be while still failing, so that the rule at fault is easy to find
+// the same words also check that ConvertWord, which the conjugator uses, converts a word the same as Convert does as text
+// go test runs a few hundred words; go test ./converter -run 'TestRoundTrip|TestConvertWordAgrees' -roundtrips=20000 runs more
+
+package converter
+
+import (
+	"flag"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+var roundTrips = flag.Int("roundtrips", 300, "how many made-up words TestRoundTrip converts between every pair of orthographies (TestConvertWordAgrees converts a tenth as many)")
+
+// the sounds words are made of, in unified orthography
+const (
+	obstruents      = "ptkqcs$="
+	sonorants       = "lmn"
+	shortVowels     = "aeiou"
+	longVowels      = "@3!%&"
+	initialSyllabic = "67+" // l', n', m' at the beginning of a word
+)
+
+// a made-up word, one sound in unified orthography for each element
+type madeUpWord []string
+
+// Generate makes a random word that is well formed, for testing/quick
+func (madeUpWord) Generate(random *rand.Rand, size int) reflect.Value {
+	for {
+		var Word madeUpWord
+		if random.Intn(8) == 0 {
+			Word = append(Word, randomSound(random, initialSyllabic))
+		}
+		for syllable := 0; syllable <= random.Intn(4); syllable++ {
+			for onset := random.Intn(3); onset > 0; onset-- {
+				Word = append(Word, randomSound(random, obstruents+obstruents+sonorants+"wy"))
+			}
+			switch random.Intn(6) {
+			case 0:
+				Word = append(Word, "*")
+			case 1, 2:
+				Word = append(Word, randomSound(random, longVowels))
+			default:
+				Word = append(Word, randomSound(random, shortVowels))
+			}
+		}
+		for coda := random.Intn(3); coda > 0; coda-- {
+			Word = append(Word, randomSound(random, obstruents+sonorants+"wy"))
+		}
+		if Word.wellFormed() {
+			return reflect.ValueOf(Word)
+		}
+	}
+}
+
+func randomSound(random *rand.Rand, sounds string) string {
+	soundRunes := []rune(sounds)
+	return string(soundRunes[random.Intn(len(soundRunes))])
+}
+
+func isOneOf(sound string, sounds string) bool {
+	return sound != "" && strings.Contains(sounds, sound)
+}
+
+// returns true if the sounds are where they can be in a word:
+// there is a full vowel, l', n' and m' only begin words and come before a consonant (and a word does not begin with a sonorant before one otherwise),
+// a schwa comes between consonants,
+// y only comes after a vowel, no more than three consonants or two vowels come together, and two vowels together are not the same vowel or a diphthong
+func (Word madeUpWord) wellFormed() bool {
+	hasVowel := false
+	consonantRun, vowelRun := 0, 0
+	for soundIndex, sound := range Word {
+		previous, next := "", ""
+		if soundIndex > 0 {
+			previous = Word[soundIndex-1]
+		}
+		if soundIndex < len(Word)-1 {
+			next = Word[soundIndex+1]
+		}
+		isVowel := isOneOf(sound, shortVowels+longVowels)
+		hasVowel = hasVowel || isVowel
+		switch {
+		case isOneOf(sound, initialSyllabic) && (soundIndex > 0 || !isOneOf(next, obstruents+sonorants)):
+			return false
+		case sound == "*" && (!isOneOf(previous, obstruents+sonorants) || !isOneOf(next, obstruents+sonorants)):
+			return false
+		case sound == "y" && !isOneOf(previous, shortVowels+longVowels):
+			return false
+		case soundIndex == 0 && isOneOf(sound, sonorants) && isOneOf(next, obstruents+sonorants+"wy"):
+			return false // a sonorant before a consonant at the beginning of a word is l', n' or m'
+		case isVowel && vowelQuality(sound) == vowelQuality(previous):
+			return false
+		case vowelQuality(sound) == "i" && (vowelQuality(previous) == "a" || vowelQuality(previous) == "e"):
+			return false // a or e before i is the diphthong ay or ey
+		}
+		if isVowel {
+			consonantRun, vowelRun = 0, vowelRun+1
+		} else if sound != "*" {
+			consonantRun, vowelRun = consonantRun+1, 0
+		}
+		if consonantRun > 3 || vowelRun > 2 {
+			return false
+		}
+	}
+	return hasVowel
+}
+
+// returns the short vowel of a long vowel, a short vowel as it is, and "" for anything else
+func vowelQuality(sound string) string {
+	if longIndex := strings.Index(longVowels, sound); sound != "" && longIndex >= 0 {
+		return string(shortVowels[longIndex])
+	}
+	if isOneOf(sound, shortVowels) {
+		return sound
+	}
+	return ""
+}
+
+// the word in unified orthography, with its allophones (voicing, syllabic sonorants) as Francis-Smith reads them
+func (Word madeUpWord) unified() string {
+	FrancisSmith := OrthographyDefinitions["francissmith"]
+	return FrancisSmith.NormalizeString(FrancisSmith.EncodeString(strings.Join(Word, "")))
+}
+
+// the orthographies that can be read back without guessing
+func reversibleOrthographies() []string {
+	var reversible []string
+	for _, orthography := range Orthographies {
+		if len(OrthographyDefinitions[orthography].Ambiguities) == 0 {
+			reversible = append(reversible, orthography)
+		}
+	}
+	return reversible
+}
+
+// converts the word written in one orthography into another and back, and returns what it is written as before and after
+func roundTrip(Word madeUpWord, fromOrthography string, toOrthography string) (string, string, string) {
+	fromStr := OrthographyDefinitions[fromOrthography].EncodeString(Word.unified())
+	toStr, _ := ConvertWord(fromStr, fromOrthography, toOrthography)
+	backStr, _ := ConvertWord(toStr, toOrthography, fromOrthography)
+	return fromStr, toStr, backStr
+}
+
+// returns the shortest and simplest word that still fails, by leaving out sounds and making long vowels short while it does
+func shrinkWord(Word madeUpWord, fails func(madeUpWord) bool) madeUpWord {
+	for shrunk := true; shrunk; {
+		shrunk = false
+		var candidates []madeUpWord
+		for soundIndex := range Word {
+			candidates = append(candidates, append(append(madeUpWord{}, Word[:soundIndex]...), Word[soundIndex+1:]...))
+			if longIndex := strings.Index(longVowels, Word[soundIndex]); longIndex >= 0 {
+				shortened := append(madeUpWord{}, Word...)
+				shortened[soundIndex] = string(shortVowels[longIndex])
+				candidates = append(candidates, shortened)
+			}
+		}
+		for _, candidate := range candidates {
+			if candidate.wellFormed() && fails(candidate) {
+				Word, shrunk = candidate, true
+				break
+			}
+		}
+	}
+	return Word
+}
+
+// every made-up word converted from a reversible orthography into another and back is written as it was
+func TestRoundTrip(t *testing.T) {
+	reversible := reversibleOrthographies()
+	for _, fromOrthography := range reversible {
+		for _, toOrthography := range reversible {
+			if fromOrthography == toOrthography {
+				continue
+			}
+			fails := func(Word madeUpWord) bool {
+				fromStr, _, backStr := roundTrip(Word, fromOrthography, toOrthography)
+				if fromStr == backStr {
+					return false
+				}
+				fromUnified, _ := NormalizeWord(fromStr, fromOrthography)
+				backUnified, _ := NormalizeWord(backStr, fromOrthography)
+				return !SameOnceCollapsed(fromUnified, backUnified, fromOrthography, toOrthography)
+			}
+			Config := &quick.Config{MaxCount: *roundTrips, Rand: rand.New(rand.NewSource(1))}
+			checkErr := quick.Check(func(Word madeUpWord) bool { return !fails(Word) }, Config)
+			if checkErr == nil {
+				continue
+			}
+			Word := shrinkWord(checkErr.(*quick.CheckError).In[0].(madeUpWord), fails)
+			fromStr, toStr, backStr := roundTrip(Word, fromOrthography, toOrthography)
+			t.Errorf("%s → %s → %s: %q → %q → %q (%q in unified orthography)",
+				fromOrthography, toOrthography, fromOrthography, fromStr, toStr, backStr, Word.unified())
+		}
+	}
+}
+
+// ConvertWord, which the conjugator converts its forms with, converts every made-up word the same as Convert does as text
+func TestConvertWordAgrees(t *testing.T) {
+	for _, fromOrthography := range Orthographies {
+		for _, toOrthography := range Orthographies {
+			differs := func(Word madeUpWord) bool {
+				fromStr := OrthographyDefinitions[fromOrthography].EncodeString(Word.unified())
+				wordStr, _ := ConvertWord(fromStr, fromOrthography, toOrthography)
+				textStr, _ := Convert(fromStr, fromOrthography, toOrthography)
+				return wordStr != textStr
+			}
+			Config := &quick.Config{MaxCount: *roundTrips / 10, Rand: rand.New(rand.NewSource(1))}
+			checkErr := quick.Check(func(Word madeUpWord) bool { return !differs(Word) }, Config)
+			if checkErr == nil {
+				continue
+			}
+			Word := shrinkWord(checkErr.(*quick.CheckError).In[0].(madeUpWord), differs)
+			fromStr := OrthographyDefinitions[fromOrthography].EncodeString(Word.unified())
+			wordStr, _ := ConvertWord(fromStr, fromOrthography, toOrthography)
+			textStr, _ := Convert(fromStr, fromOrthography, toOrthography)
+			t.Errorf("%s → %s: ConvertWord(%q) = %q, but Convert gives %q", fromOrthography, toOrthography, fromStr, wordStr, textStr)
+		}
+	}
+}
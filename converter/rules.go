@@ -0,0 +1,489 @@
+// the rule engine that the orthographies are written in
+// every orthography is a data file in converter/orthographies with two lists of rules:
+// "normalize" turns the orthography into unified orthography, and "encode" turns unified orthography back into it
+// the rules are applied in order, like the strings.Replace chains they replaced, so that a rule sees the output of every rule above it
+// "losses" lists what the orthography writes like something else, as rules in unified orthography applied in a single pass (see Collapse)
+//
+// a rule replaces "from" with "to", optionally only when the characters around it match "left" and "right":
+//	{"from": "t", "to": "d", "left": ["not consonant|sonorant|edge"], "right": ["not consonant|delineator|edge"]}
+// left lists the characters before "from", nearest last, and right lists the characters after it, nearest first
+// each of these is a character, a class name, or several of them separated by | (any one of them matching is enough)
+// "not " in front matches anything but those, and " and " joins conditions that must all match
+// an empty "from" inserts "to" wherever the context matches
+//
+// a list of rules in place of a rule is applied in a single pass from left to right, trying every rule at every character,
+// which is how the loops over characters worked: left context sees what the pass has already replaced, right context sees what it has not yet reached
+// {"include": "francissmith"} in place of a rule applies every rule of another orthography in the same direction
+//
+// the classes are:
+// edge: before the beginning or after the end of the word
+// boundary: an edge or a delineator
+// any: any character (not an edge)
+// consonant, sonorant, semivowel, delineator, voiceable, lowbackvowel, longvowel: the Is functions in converter.go
+// an orthography can also define its own classes in "classes", as a string of every character in the class
+
+package converter
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed orthographies/*.json
+var orthographyFiles embed.FS
+
+// the folder that ConverterInit reads orthography files from, so that new or changed orthographies do not need a rebuild
+var OrthographyFolder = "converter/orthographies"
+
+type Rule struct { // a single replacement
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Left    []string `json:"left,omitempty"`
+	Right   []string `json:"right,omitempty"`
+	Include string   `json:"include,omitempty"` // the name of an orthography whose rules are applied here instead
+	Note    string   `json:"note,omitempty"`    // for comments, since json has none
+}
+
+type Step struct { // one pass over the word, with one rule or several rules applied together
+	Rules []Rule
+}
+
+type Orthography struct { // an orthography as it is written in its data file
+	Name              string            `json:"name"`        // the value used by the pages and the api, e.g. "francissmith"
+	DisplayName       string            `json:"displayname"` // the name shown on the pages, e.g. "Francis-Smith"
+	Shared            bool              `json:"shared"`      // a set of rules included by other orthographies, which is not an orthography of its own
+	Lowercase         bool              `json:"lowercase"`   // words are never capitalized, e.g. in ipa where capitals are different sounds
+	Classes           map[string]string `json:"classes,omitempty"`
+	Normalize         []Step            `json:"normalize"`
+	Encode            []Step            `json:"encode"`
+	Ambiguities       []Ambiguity       `json:"ambiguities,omitempty"`
+	Losses            []Rule            `json:"losses,omitempty"`            // what this orthography writes like something else, as rules in unified orthography from what was written to what is read back (see Collapse)
+	NormalizeExamples [][2]string       `json:"normalizeexamples,omitempty"` // pairs of a word and the unified orthography it should normalize to
+	EncodeExamples    [][2]string       `json:"encodeexamples,omitempty"`    // pairs of unified orthography and the word it should encode to
+}
+
+type Ambiguity struct { // a character in unified orthography that this orthography does not tell apart from others
+	From string   `json:"from"`
+	To   []string `json:"to"` // the other characters it could be, most likely first
+	Note string   `json:"note,omitempty"`
+}
+
+type TraceStep struct { // a pass over the word that changed it, for explaining a conversion
+	Orthography string   `json:"orthography"` // the file the rules are in, which can be a shared rule set
+	Rules       []string `json:"rules"`       // the rules that changed the word, written as in Rule.String
+	Before      string   `json:"before"`
+	After       string   `json:"after"`
+}
+
+// every orthography the converter knows, by name
+var OrthographyDefinitions = make(map[string]*Orthography)
+
+// the classes shared by every orthography
+var ruleClasses = map[string]func(string) bool{
+	"consonant":    IsConsonant,
+	"sonorant":     IsSonorant,
+	"semivowel":    IsSemivowel,
+	"delineator":   IsDelineator,
+	"voiceable":    IsAllophonicallyVoiced,
+	"lowbackvowel": IsLowBackVowel,
+	"longvowel":    IsLongVowel,
+}
+
+// the deepest that includes can go, so that two orthographies including each other cannot loop forever
+const maxIncludeDepth = 8
+
+func init() { // the built-in orthographies and wordlist are always available, even to programs that never call ConverterInit
+	loadErr := loadOrthographyFiles(orthographyFiles, "orthographies")
+	if loadErr == nil {
+		loadErr = checkOrthographies()
+	}
+	if loadErr != nil {
+		fmt.Println(loadErr)
+	}
+	Table, parseErr := ParseSubstitutions(substitutionsFile) // after the orthographies, since the table names them
+	if parseErr != nil {
+		fmt.Println(parseErr)
+	}
+	SetSubstitutions(Table)
+	KnownWords = ParseWordlist(wordlistFile) // after the orthographies and substitutions, since the words are converted to be compared
+}
+
+// a step can be written as a single rule or as a list of rules
+func (RuleStep *Step) UnmarshalJSON(data []byte) error {
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		return json.Unmarshal(data, &RuleStep.Rules)
+	}
+	var SingleRule Rule
+	unmarshalErr := json.Unmarshal(data, &SingleRule)
+	RuleStep.Rules = []Rule{SingleRule}
+	return unmarshalErr
+}
+
+// loads the orthographies built into the program, then any in OrthographyFolder, which replace built-in ones with the same name,
+// and the substitution table at SubstitutionsPath
+func LoadOrthographies() error {
+	loadErr := loadOrthographyFiles(orthographyFiles, "orthographies")
+	if loadErr != nil {
+		return loadErr
+	}
+	if _, statErr := os.Stat(OrthographyFolder); statErr == nil {
+		loadErr = loadOrthographyFiles(os.DirFS(OrthographyFolder), ".")
+		if loadErr != nil {
+			return loadErr
+		}
+	}
+	checkErr := checkOrthographies()
+	if checkErr != nil {
+		return checkErr
+	}
+	return loadSubstitutions()
+}
+
+// checks every orthography once all of them are loaded, since they can include each other
+func checkOrthographies() error {
+	for _, Definition := range OrthographyDefinitions {
+		checkErr := Definition.Check()
+		if checkErr != nil {
+			return checkErr
+		}
+	}
+	return nil
+}
+
+// reads every .json file in a folder as an orthography
+func loadOrthographyFiles(fileSystem fs.FS, folder string) error {
+	fileNames, globErr := fs.Glob(fileSystem, path.Join(folder, "*.json"))
+	if globErr != nil {
+		return globErr
+	}
+	sort.Strings(fileNames)
+	for _, fileName := range fileNames {
+		fileContents, readErr := fs.ReadFile(fileSystem, fileName)
+		if readErr != nil {
+			return readErr
+		}
+		Definition, parseErr := ParseOrthography(fileContents)
+		if parseErr != nil {
+			return fmt.Errorf("%s: %w", fileName, parseErr)
+		}
+		AddOrthography(Definition)
+	}
+	return nil
+}
+
+// reads an orthography from the contents of its data file
+func ParseOrthography(fileContents []byte) (*Orthography, error) {
+	var Definition Orthography
+	unmarshalErr := json.Unmarshal(fileContents, &Definition)
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	if Definition.Name == "" {
+		return nil, errors.New("orthography has no name")
+	}
+	if Definition.DisplayName == "" {
+		Definition.DisplayName = Definition.Name
+	}
+	return &Definition, nil
+}
+
+// makes an orthography available to the converter, the conjugator, and the api
+// an orthography that is not already known is added to the end of Orthographies
+func AddOrthography(Definition *Orthography) {
+	OrthographyDefinitions[Definition.Name] = Definition
+	if Definition.Shared {
+		return
+	}
+	known := false
+	for _, orthography := range Orthographies {
+		if orthography == Definition.Name {
+			known = true
+		}
+	}
+	if !known {
+		Orthographies = append(Orthographies, Definition.Name)
+	}
+	OrthographyNames[Definition.Name] = Definition.DisplayName
+}
+
+// checks that every class and include in the rules exists, and that the examples convert as they should
+func (Definition *Orthography) Check() error {
+	for _, steps := range [][]Step{Definition.Normalize, Definition.Encode} {
+		for _, RuleStep := range steps {
+			for _, RuleEntry := range RuleStep.Rules {
+				if RuleEntry.Include != "" {
+					if _, found := OrthographyDefinitions[RuleEntry.Include]; !found {
+						return fmt.Errorf("%s: included orthography %q does not exist", Definition.Name, RuleEntry.Include)
+					}
+					continue
+				}
+				for _, element := range append(append([]string{}, RuleEntry.Left...), RuleEntry.Right...) {
+					checkErr := Definition.checkElement(element)
+					if checkErr != nil {
+						return fmt.Errorf("%s: rule %q → %q: %w", Definition.Name, RuleEntry.From, RuleEntry.To, checkErr)
+					}
+				}
+			}
+		}
+	}
+	for _, RuleEntry := range Definition.Losses {
+		for _, element := range append(append([]string{}, RuleEntry.Left...), RuleEntry.Right...) {
+			checkErr := Definition.checkElement(element)
+			if checkErr != nil {
+				return fmt.Errorf("%s: loss %q → %q: %w", Definition.Name, RuleEntry.From, RuleEntry.To, checkErr)
+			}
+		}
+	}
+	for _, ThisAmbiguity := range Definition.Ambiguities {
+		if len([]rune(ThisAmbiguity.From)) != 1 || len(ThisAmbiguity.To) == 0 {
+			return fmt.Errorf("%s: ambiguity %q must be a single character with at least one alternative", Definition.Name, ThisAmbiguity.From)
+		}
+	}
+	for _, example := range Definition.NormalizeExamples {
+		if unifiedString := Definition.NormalizeString(example[0]); unifiedString != example[1] {
+			return fmt.Errorf("%s: %q normalizes to %q, not %q", Definition.Name, example[0], unifiedString, example[1])
+		}
+	}
+	for _, example := range Definition.EncodeExamples {
+		if outputStr := Definition.EncodeString(example[0]); outputStr != example[1] {
+			return fmt.Errorf("%s: %q encodes to %q, not %q", Definition.Name, example[0], outputStr, example[1])
+		}
+	}
+	return nil
+}
+
+// checks that every class named in a context element exists
+func (Definition *Orthography) checkElement(element string) error {
+	for _, condition := range strings.Split(element, " and ") {
+		condition = strings.TrimPrefix(strings.TrimSpace(condition), "not ")
+		for _, alternative := range strings.Split(condition, "|") {
+			alternative = strings.TrimSpace(alternative)
+			if len([]rune(alternative)) == 1 {
+				continue
+			}
+			_, sharedClass := ruleClasses[alternative]
+			_, ownClass := Definition.Classes[alternative]
+			if !sharedClass && !ownClass && alternative != "edge" && alternative != "boundary" && alternative != "any" {
+				return fmt.Errorf("unknown class %q", alternative)
+			}
+		}
+	}
+	return nil
+}
+
+// turns a word in this orthography into unified orthography
+func (Definition *Orthography) NormalizeString(inputStr string) string {
+	return string(Definition.applySteps([]rune(inputStr), false, 0, nil))
+}
+
+// turns a word in unified orthography into this orthography
+func (Definition *Orthography) EncodeString(inputStr string) string {
+	return string(Definition.applySteps([]rune(inputStr), true, 0, nil))
+}
+
+// turns a word (or words) in unified orthography into what is read back after it is written in this orthography, where it writes something like something else
+// unlike Ambiguities, these are not guessed at: two words that are the same once collapsed differ only in what the orthography loses, e.g. in cmd/crosscheck
+// the rules are applied until they change nothing, since what one of them reads back can be lost again (e.g. eyiy is read back as e'y)
+func (Definition *Orthography) Collapse(unifiedStr string) string {
+	input := []rune(unifiedStr)
+	for pass := 0; pass <= len(input); pass++ { // at most as many passes as there are characters, in case two losses undo each other
+		output, _ := Definition.applyStep(input, Step{Definition.Losses})
+		if string(output) == string(input) {
+			break
+		}
+		input = output
+	}
+	return string(input)
+}
+
+// returns true if two words (or texts) in unified orthography are the same once what the orthographies write like something else is collapsed,
+// with their allophones (voicing, syllabic sonorants) as Francis-Smith reads them, since what is read back can have other allophones
+func SameOnceCollapsed(firstStr string, secondStr string, orthographies ...string) bool {
+	for _, orthography := range orthographies {
+		if Definition, found := OrthographyDefinitions[orthography]; found {
+			firstStr, secondStr = Definition.Collapse(firstStr), Definition.Collapse(secondStr)
+		}
+	}
+	FrancisSmith := OrthographyDefinitions["francissmith"]
+	return FrancisSmith.NormalizeString(FrancisSmith.EncodeString(firstStr)) == FrancisSmith.NormalizeString(FrancisSmith.EncodeString(secondStr))
+}
+
+// like NormalizeString, but also returns every step that changed the word
+func (Definition *Orthography) TraceNormalize(inputStr string) (string, []TraceStep) {
+	trace := []TraceStep{}
+	return string(Definition.applySteps([]rune(inputStr), false, 0, &trace)), trace
+}
+
+// like EncodeString, but also returns every step that changed the word
+func (Definition *Orthography) TraceEncode(inputStr string) (string, []TraceStep) {
+	trace := []TraceStep{}
+	return string(Definition.applySteps([]rune(inputStr), true, 0, &trace)), trace
+}
+
+// writes a rule the way linguists write sound changes, e.g. "t → d / edge _ not consonant"
+func (RuleEntry Rule) String() string {
+	from, to := RuleEntry.From, RuleEntry.To
+	if from == "" {
+		from = "∅"
+	}
+	if to == "" {
+		to = "∅"
+	}
+	if len(RuleEntry.Left) == 0 && len(RuleEntry.Right) == 0 {
+		return fmt.Sprintf("%s → %s", from, to)
+	}
+	return fmt.Sprintf("%s → %s / %s _ %s", from, to, strings.Join(RuleEntry.Left, " "), strings.Join(RuleEntry.Right, " "))
+}
+
+// applies the normalize or encode rules in order. if trace is not nil, every step that changes the word is added to it
+func (Definition *Orthography) applySteps(input []rune, encode bool, depth int, trace *[]TraceStep) []rune {
+	steps := Definition.Normalize
+	if encode {
+		steps = Definition.Encode
+	}
+	for _, RuleStep := range steps {
+		if len(RuleStep.Rules) == 1 && RuleStep.Rules[0].Include != "" {
+			IncludedDefinition, found := OrthographyDefinitions[RuleStep.Rules[0].Include]
+			if found && depth < maxIncludeDepth {
+				input = IncludedDefinition.applySteps(input, encode, depth+1, trace)
+			}
+			continue
+		}
+		output, appliedRules := Definition.applyStep(input, RuleStep)
+		if trace != nil && string(output) != string(input) {
+			var ruleDescriptions []string
+			for _, ruleIndex := range appliedRules {
+				ruleDescriptions = append(ruleDescriptions, RuleStep.Rules[ruleIndex].String())
+			}
+			*trace = append(*trace, TraceStep{Definition.Name, ruleDescriptions, string(input), string(output)})
+		}
+		input = output
+	}
+	return input
+}
+
+// a single pass over the word from left to right. at every character the first rule that matches is applied
+// also returns the index of every rule that was applied, in the order they were first applied
+func (Definition *Orthography) applyStep(input []rune, RuleStep Step) ([]rune, []int) {
+	output := make([]rune, 0, len(input))
+	var appliedRules []int
+	charIndex := 0
+	for charIndex <= len(input) {
+		replaced := false
+		for ruleIndex, RuleEntry := range RuleStep.Rules {
+			from := []rune(RuleEntry.From)
+			if charIndex+len(from) > len(input) || string(input[charIndex:charIndex+len(from)]) != RuleEntry.From {
+				continue
+			}
+			if !Definition.leftMatches(output, RuleEntry.Left) || !Definition.rightMatches(input[charIndex+len(from):], RuleEntry.Right) {
+				continue
+			}
+			output = append(output, []rune(RuleEntry.To)...)
+			if !containsIndex(appliedRules, ruleIndex) {
+				appliedRules = append(appliedRules, ruleIndex)
+			}
+			if len(from) > 0 {
+				charIndex += len(from)
+				replaced = true
+			}
+			break // an insertion still lets the character after it through below
+		}
+		if !replaced {
+			if charIndex < len(input) {
+				output = append(output, input[charIndex])
+			}
+			charIndex++
+		}
+	}
+	return output, appliedRules
+}
+
+func containsIndex(indices []int, index int) bool {
+	for _, thisIndex := range indices {
+		if thisIndex == index {
+			return true
+		}
+	}
+	return false
+}
+
+// checks the characters before a match, nearest last
+func (Definition *Orthography) leftMatches(before []rune, left []string) bool {
+	for elementIndex := range left {
+		charIndex := len(before) - len(left) + elementIndex
+		if charIndex < 0 {
+			if !Definition.elementMatches(left[elementIndex], 0, false) {
+				return false
+			}
+		} else if !Definition.elementMatches(left[elementIndex], before[charIndex], true) {
+			return false
+		}
+	}
+	return true
+}
+
+// checks the characters after a match, nearest first
+func (Definition *Orthography) rightMatches(after []rune, right []string) bool {
+	for elementIndex, element := range right {
+		if elementIndex >= len(after) {
+			if !Definition.elementMatches(element, 0, false) {
+				return false
+			}
+		} else if !Definition.elementMatches(element, after[elementIndex], true) {
+			return false
+		}
+	}
+	return true
+}
+
+// checks a single context element against a character, or against an edge if exists is false
+func (Definition *Orthography) elementMatches(element string, character rune, exists bool) bool {
+	for _, condition := range strings.Split(element, " and ") {
+		condition = strings.TrimSpace(condition)
+		negated := strings.HasPrefix(condition, "not ")
+		condition = strings.TrimPrefix(condition, "not ")
+		matched := false
+		for _, alternative := range strings.Split(condition, "|") {
+			if Definition.alternativeMatches(strings.TrimSpace(alternative), character, exists) {
+				matched = true
+				break
+			}
+		}
+		if matched == negated {
+			return false
+		}
+	}
+	return true
+}
+
+// checks a single character or class against a character
+func (Definition *Orthography) alternativeMatches(alternative string, character rune, exists bool) bool {
+	switch alternative {
+	case "edge":
+		return !exists
+	case "boundary":
+		return !exists || IsDelineator(string(character))
+	case "any":
+		return exists
+	}
+	if !exists {
+		return false
+	}
+	if len([]rune(alternative)) == 1 {
+		return []rune(alternative)[0] == character
+	}
+	if classFunction, found := ruleClasses[alternative]; found {
+		return classFunction(string(character))
+	}
+	if classCharacters, found := Definition.Classes[alternative]; found {
+		return strings.ContainsRune(classCharacters, character)
+	}
+	return false
+}
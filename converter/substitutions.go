@@ -0,0 +1,194 @@
+// the character substitution table: sequences that are easy to type on any keyboard, and the characters they stand for (a/ for ă, o! for ô, * for ɨ, ...)
+// the table is data, in substitutions.json, and is applied to every word before it is normalized, by the converter and by the conjugator
+// the server reads the file at SubstitutionsPath, so substitutions can be added without a rebuild
+// /convert/substitute?text=...&orthography=rand returns the text with the substitutions made, so that a page can show special characters as they are typed
+
+package converter
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+//go:embed substitutions.json
+var substitutionsFile []byte
+
+// the file that LoadOrthographies reads the substitution table from
+var SubstitutionsPath = "converter/substitutions.json"
+
+type Substitution struct { // a sequence that can be typed instead of a character
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+	Orthographies []string `json:"orthographies,omitempty"` // the orthographies that write To; every orthography if there are none
+	Note          string   `json:"note,omitempty"`
+}
+
+type SubstitutionPreview struct { // what /convert/substitute returns
+	Input       string `json:"input"`
+	Orthography string `json:"orthography"`
+	Substituted string `json:"substituted"`
+}
+
+// the substitution table, built in and then read from SubstitutionsPath by LoadOrthographies
+var Substitutions []Substitution
+
+// the substitutions of every orthography, and with capital versions for text as it is typed, made the first time they are needed
+var (
+	substitutionReplacers        = make(map[string]*strings.Replacer)
+	capitalSubstitutionReplacers = make(map[string]*strings.Replacer)
+	substitutionReplacersMutex   sync.Mutex
+)
+
+// reads a substitution table from the contents of its file
+func ParseSubstitutions(fileContents []byte) ([]Substitution, error) {
+	var Table struct {
+		Substitutions []Substitution `json:"substitutions"`
+	}
+	unmarshalErr := json.Unmarshal(fileContents, &Table)
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	for _, ThisSubstitution := range Table.Substitutions {
+		if ThisSubstitution.From == "" {
+			return nil, fmt.Errorf("substitution to %q has nothing to substitute", ThisSubstitution.To)
+		}
+		for _, orthography := range ThisSubstitution.Orthographies {
+			if _, found := OrthographyDefinitions[orthography]; !found {
+				return nil, fmt.Errorf("substitution %q → %q: orthography %q does not exist", ThisSubstitution.From, ThisSubstitution.To, orthography)
+			}
+		}
+	}
+	return Table.Substitutions, nil
+}
+
+// reads the substitution table at SubstitutionsPath, if there is one, in place of the built-in one
+func loadSubstitutions() error {
+	fileContents, readErr := os.ReadFile(SubstitutionsPath)
+	if os.IsNotExist(readErr) {
+		fileContents, readErr = substitutionsFile, nil
+	}
+	if readErr != nil {
+		return readErr
+	}
+	Table, parseErr := ParseSubstitutions(fileContents)
+	if parseErr != nil {
+		return fmt.Errorf("%s: %w", SubstitutionsPath, parseErr)
+	}
+	SetSubstitutions(Table)
+	return nil
+}
+
+// SetSubstitutions replaces the substitution table
+func SetSubstitutions(Table []Substitution) {
+	substitutionReplacersMutex.Lock()
+	defer substitutionReplacersMutex.Unlock()
+	Substitutions = Table
+	substitutionReplacers = make(map[string]*strings.Replacer)
+	capitalSubstitutionReplacers = make(map[string]*strings.Replacer)
+}
+
+// returns the replacer for an orthography from a cache of them, making it if it is not there yet
+func substitutionReplacer(cache map[string]*strings.Replacer, orthographyChoice string, withCapitals bool) *strings.Replacer {
+	substitutionReplacersMutex.Lock()
+	defer substitutionReplacersMutex.Unlock()
+	if Replacer, found := cache[orthographyChoice]; found {
+		return Replacer
+	}
+	var pairs []string
+	for _, ThisSubstitution := range SubstitutionsFor(orthographyChoice) {
+		pairs = append(pairs, ThisSubstitution.From, ThisSubstitution.To)
+		if capitalFrom := strings.ToUpper(ThisSubstitution.From); withCapitals && capitalFrom != ThisSubstitution.From {
+			pairs = append(pairs, capitalFrom, strings.ToUpper(ThisSubstitution.To))
+		}
+	}
+	cache[orthographyChoice] = strings.NewReplacer(pairs...)
+	return cache[orthographyChoice]
+}
+
+// returns the substitutions that apply to an orthography, in the order of the table
+func SubstitutionsFor(orthographyChoice string) []Substitution {
+	var OrthographySubstitutions []Substitution
+	for _, ThisSubstitution := range Substitutions {
+		applies := len(ThisSubstitution.Orthographies) == 0
+		for _, orthography := range ThisSubstitution.Orthographies {
+			applies = applies || orthography == orthographyChoice
+		}
+		if applies {
+			OrthographySubstitutions = append(OrthographySubstitutions, ThisSubstitution)
+		}
+	}
+	return OrthographySubstitutions
+}
+
+// Substitute makes every substitution of an orthography in a lowercase word, e.g. "mu/n'chu/" becomes "mŭn'chŭ" in rand
+func Substitute(inputStr string, orthographyChoice string) string {
+	return substitutionReplacer(substitutionReplacers, orthographyChoice, false).Replace(inputStr)
+}
+
+// makes every substitution of an orthography in text as it was typed, so that e.g. A/ becomes Ă
+func substituteTyped(inputStr string, orthographyChoice string) string {
+	return substitutionReplacer(capitalSubstitutionReplacers, orthographyChoice, true).Replace(inputStr)
+}
+
+// returns true if a substitution of the orthography ends at charIndex, e.g. the / of u/ in rand,
+// so that the character is part of the word even though it would be punctuation on its own
+func endsSubstitution(inputRunes []rune, charIndex int, orthographyChoice string) bool {
+	for _, ThisSubstitution := range SubstitutionsFor(orthographyChoice) {
+		fromRunes := []rune(ThisSubstitution.From)
+		startIndex := charIndex + 1 - len(fromRunes)
+		if len(fromRunes) > 1 && startIndex >= 0 && strings.EqualFold(string(inputRunes[startIndex:charIndex+1]), ThisSubstitution.From) {
+			return true
+		}
+	}
+	return false
+}
+
+// PreviewSubstitutions makes the substitutions of an orthography (or "auto" to detect it) in the words of a text,
+// leaving escaped sequences, punctuation and capitals as they were typed, for showing the text as it is typed
+func PreviewSubstitutions(inputStr string, orthographyChoice string) SubstitutionPreview {
+	var Preview SubstitutionPreview
+	Preview.Input = inputStr
+	if orthographyChoice == "" || orthographyChoice == "auto" {
+		orthographyChoice = DetectOrthography(inputStr).Orthography
+	}
+	Preview.Orthography = orthographyChoice
+	Scanner, _ := newEscapeScanner(DefaultEscapeMarkers)
+	Scanner.KeepMarkers = true
+	Pieces, _ := Scanner.split(inputStr) // text that is still being typed can have a { that is not closed yet
+	var outputStr strings.Builder
+	for _, Piece := range Pieces {
+		if Piece.Escaped {
+			outputStr.WriteString(Piece.InputString)
+			continue
+		}
+		for _, Token := range tokenizeWords(Piece.InputString, orthographyChoice) {
+			if Token.Punctuation {
+				outputStr.WriteString(Token.InputString)
+			} else {
+				outputStr.WriteString(substituteTyped(Token.TypedString, orthographyChoice))
+			}
+		}
+	}
+	Preview.Substituted = outputStr.String()
+	return Preview
+}
+
+// handles /convert/substitute, returning the preview as json, or as text with &format=text
+func substituteHandler(writer http.ResponseWriter, reader *http.Request) {
+	Preview := PreviewSubstitutions(reader.FormValue("text"), reader.FormValue("orthography"))
+	if reader.FormValue("format") == "text" {
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(writer, Preview.Substituted)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encodeErr := json.NewEncoder(writer).Encode(Preview)
+	if encodeErr != nil {
+		fmt.Println(encodeErr)
+	}
+}
@@ -0,0 +1,206 @@
+// splits words into syllables and predicts where the stress falls, for learners reading long conjugated forms
+// this works on unified orthography, where every sound is a single character:
+// the nucleus of a syllable is a vowel, a schwa, or a syllabic l, n or m (6 7 + 8 9 0)
+// a single consonant between two nuclei begins the second syllable (ke·salk), and in a cluster only the last one does (mal·ko)
+// the stress is predicted, not read from a dictionary: it falls on the first syllable with a long vowel,
+// and otherwise on the second-to-last syllable, moving to the nearest full vowel if that syllable is a schwa
+
+package converter
+
+import (
+	"strings"
+)
+
+type Syllable struct { // one syllable of a word in unified orthography
+	Unified  string `json:"unified"`
+	Stressed bool   `json:"stressed"`
+}
+
+// written between syllables, e.g. ke·salk
+const SyllableSeparator = "·"
+
+// written before the stressed syllable, as in ipa, e.g. ˈke·salk
+const StressMark = "ˈ"
+
+// returns true if the character can be the nucleus of a syllable
+func isSyllableNucleus(char string) bool {
+	return isVowel(char) || isSyllabicSonorant(char)
+}
+
+// returns true if the character is a vowel or a schwa
+func isVowel(char string) bool {
+	switch char {
+	case
+		"a",
+		"e",
+		"i",
+		"o",
+		"u",
+		"*":
+		return true
+	}
+	return IsLongVowel(char)
+}
+
+// returns true if the character is a syllabic l, n or m
+func isSyllabicSonorant(char string) bool {
+	switch char {
+	case
+		"6",
+		"7",
+		"+",
+		"8",
+		"9",
+		"0":
+		return true
+	}
+	return false
+}
+
+// returns true if the character is a sound, as opposed to e.g. a hyphen or an apostrophe
+func isSound(char string) bool {
+	switch char {
+	case
+		"D",
+		"B",
+		"G",
+		"J",
+		"V":
+		return true // the semi-voiced consonants
+	}
+	return isSyllableNucleus(char) || IsConsonant(char) || IsSonorant(char)
+}
+
+// splits a word in unified orthography into syllables, with the predicted stress marked
+// characters that are not sounds are kept as syllables of their own, and the parts of a word on either side of them (e.g. a hyphen) are stressed separately
+func Syllabify(unifiedStr string) []Syllable {
+	var Syllables []Syllable
+	var part []rune // the sounds since the last character that was not one
+	for _, char := range unifiedStr {
+		if isSound(string(char)) {
+			part = append(part, char)
+			continue
+		}
+		Syllables = append(Syllables, syllabifyPart(part)...)
+		Syllables = append(Syllables, Syllable{Unified: string(char)})
+		part = nil
+	}
+	return append(Syllables, syllabifyPart(part)...)
+}
+
+// splits a run of sounds into syllables and marks the stress
+func syllabifyPart(part []rune) []Syllable {
+	var Syllables []Syllable
+	if len(part) == 0 {
+		return Syllables
+	}
+	var nuclei []int
+	for charIndex, char := range part {
+		if isSyllableNucleus(string(char)) && !(isSyllabicSonorant(string(char)) && charIndex+1 < len(part) && isVowel(string(part[charIndex+1]))) {
+			nuclei = append(nuclei, charIndex) // a syllabic sonorant before a vowel begins that vowel's syllable instead (kep·mi·te'·tm)
+		}
+	}
+	syllableStart := 0
+	for nucleusIndex := 1; nucleusIndex < len(nuclei); nucleusIndex++ {
+		syllableEnd := nuclei[nucleusIndex] // two nuclei side by side are split between them (we·ji·a'p)
+		if nuclei[nucleusIndex]-nuclei[nucleusIndex-1] > 1 {
+			syllableEnd = nuclei[nucleusIndex] - 1 // otherwise the consonant just before the second nucleus begins its syllable
+		}
+		Syllables = append(Syllables, Syllable{Unified: string(part[syllableStart:syllableEnd])})
+		syllableStart = syllableEnd
+	}
+	Syllables = append(Syllables, Syllable{Unified: string(part[syllableStart:])})
+
+	if len(nuclei) > 1 { // words of one syllable are not marked
+		Syllables[predictStress(Syllables)].Stressed = true
+	}
+	return Syllables
+}
+
+// returns the index of the syllable the stress is predicted to fall on, in a part of a word with more than one syllable
+func predictStress(Syllables []Syllable) int {
+	for syllableIndex, ThisSyllable := range Syllables {
+		for _, char := range ThisSyllable.Unified {
+			if IsLongVowel(string(char)) {
+				return syllableIndex
+			}
+		}
+	}
+	stressIndex := len(Syllables) - 2
+	for searchIndex := stressIndex; searchIndex >= 0; searchIndex-- { // look back for a full vowel, then forward
+		if hasFullVowel(Syllables[searchIndex].Unified) {
+			return searchIndex
+		}
+	}
+	for searchIndex := stressIndex + 1; searchIndex < len(Syllables); searchIndex++ {
+		if hasFullVowel(Syllables[searchIndex].Unified) {
+			return searchIndex
+		}
+	}
+	return stressIndex
+}
+
+// returns true if a syllable's nucleus is a vowel that is not a schwa
+func hasFullVowel(syllableStr string) bool {
+	return strings.ContainsAny(syllableStr, "aeiou")
+}
+
+// writes the syllables of a word in an orthography, with separators and the stress mark
+// the word is encoded as a whole, so that every rule sees the whole word, and then cut where each syllable begins;
+// a syllable that does not begin at a clear point in the encoded word is joined to the one before it
+func (Definition *Orthography) EncodeSyllables(Syllables []Syllable) string {
+	var unifiedPrefix strings.Builder
+	for _, ThisSyllable := range Syllables {
+		unifiedPrefix.WriteString(ThisSyllable.Unified)
+	}
+	encodedWord := Definition.EncodeString(unifiedPrefix.String())
+	unifiedPrefix.Reset()
+
+	var outputStr strings.Builder
+	lastCut := 0
+	previousSound := false
+	pieceStressed := len(Syllables) > 0 && Syllables[0].Stressed
+	for syllableIndex, ThisSyllable := range Syllables {
+		thisSound := ThisSyllable.Unified != "" && isSound(string([]rune(ThisSyllable.Unified)[0]))
+		if syllableIndex > 0 {
+			encodedPrefix := Definition.EncodeString(unifiedPrefix.String())
+			if len(encodedPrefix) > lastCut && len(encodedPrefix) < len(encodedWord) && strings.HasPrefix(encodedWord, encodedPrefix) {
+				writeSyllable(&outputStr, encodedWord[lastCut:len(encodedPrefix)], pieceStressed)
+				if previousSound && thisSound {
+					outputStr.WriteString(SyllableSeparator)
+				}
+				lastCut = len(encodedPrefix)
+				pieceStressed = false
+			}
+			pieceStressed = pieceStressed || ThisSyllable.Stressed
+		}
+		unifiedPrefix.WriteString(ThisSyllable.Unified)
+		previousSound = thisSound
+	}
+	writeSyllable(&outputStr, encodedWord[lastCut:], pieceStressed)
+	return outputStr.String()
+}
+
+// writes one encoded syllable, with the stress mark before it if it is stressed
+func writeSyllable(outputStr *strings.Builder, syllableStr string, stressed bool) {
+	if stressed && syllableStr != "" {
+		outputStr.WriteString(StressMark)
+	}
+	outputStr.WriteString(syllableStr)
+}
+
+// converts a single lowercase word from one orthography to another, split into syllables with the stress marked
+// used by the conjugator, in the same way as ConvertWord
+func SyllabifyWord(inputStr string, fromOrthography string, toOrthography string) (string, error) {
+	if _, found := OrthographyNames[toOrthography]; !found {
+		return inputStr, ErrUnknownOrthography
+	}
+	if inputStr == "" {
+		return inputStr, nil
+	}
+	unifiedString, normalizeErr := NormalizeWord(inputStr, fromOrthography)
+	if normalizeErr != nil {
+		return inputStr, normalizeErr
+	}
+	return OrthographyDefinitions[toOrthography].EncodeSyllables(Syllabify(unifiedString)), nil
+}
@@ -0,0 +1,126 @@
+// splits text into words and everything between them, so that only words are converted
+// spaces, tabs, line breaks, punctuation, quotes, dashes and digits are kept exactly as they are,
+// and every word keeps its case: lowercase, a capital initial letter, or ALL CAPS
+// apostrophes, schwas and the characters used in typing substitutions (: / ! -) are part of a word when they are inside it,
+// or end a substitution of the orthography the text is in, so that e.g. wejia'p, gesalg'p, pa/t, o!pla and rand mu/n'chu/ are single words but 'pa' and eliet! are not
+
+package converter
+
+import (
+	"strings"
+	"unicode"
+)
+
+// splits a piece of text that is not escaped into words and the punctuation and whitespace between them
+// words are lowercased, with their capitals recorded in UpperInitial and AllCaps
+func tokenizeWords(inputStr string, orthographyChoice string) []ConversionString {
+	var Tokens []ConversionString
+	var currentToken []rune
+	currentIsWord := false
+	quoted := false // if the current word comes just after an apostrophe, which makes one at its end a closing quote
+	addToken := func() {
+		if len(currentToken) == 0 {
+			return
+		}
+		var Token ConversionString
+		Token.InputString = string(currentToken)
+		Token.TypedString = Token.InputString
+		Token.Punctuation = !currentIsWord
+		if currentIsWord {
+			Token.UpperInitial, Token.AllCaps = capitalsOf(Token.InputString)
+			Token.InputString = NormalizeUnicode(strings.ToLower(Token.InputString))
+		}
+		Tokens = append(Tokens, Token)
+		currentToken = nil
+	}
+
+	inputRunes := []rune(inputStr)
+	for charIndex, char := range inputRunes {
+		isWordChar := isWordCharacter(inputRunes, charIndex, currentIsWord && len(currentToken) > 0, quoted, orthographyChoice)
+		if isWordChar != currentIsWord {
+			addToken()
+			currentIsWord = isWordChar
+			quoted = isWordChar && charIndex > 0 && isApostrophe(inputRunes[charIndex-1])
+		}
+		currentToken = append(currentToken, char)
+	}
+	addToken()
+	return Tokens
+}
+
+// returns true if the character at charIndex belongs to a word, given whether the characters before it do, if the word is in quotes,
+// and the orthography it is in
+func isWordCharacter(inputRunes []rune, charIndex int, inWord bool, quoted bool, orthographyChoice string) bool {
+	char := inputRunes[charIndex]
+	nextIsLetter := charIndex+1 < len(inputRunes) && unicode.IsLetter(inputRunes[charIndex+1])
+	if isApostrophe(char) { // inside a word, or marking a long vowel at the end of one (ela'q, ke', but not 'ke')
+		return inWord && (nextIsLetter || (!quoted && strings.ContainsRune("aeiouAEIOU", inputRunes[charIndex-1])))
+	}
+	if unicode.IsLetter(char) || unicode.Is(unicode.Mn, char) { // letters and combining diacritics
+		return true
+	}
+	switch char {
+	case '*': // a schwa, which can begin a word
+		return inWord || nextIsLetter
+	case ':', '/', '!', '-': // only between letters or in a substitution, so that they are still punctuation at the end of a word
+		if inWord && char == ':' && strings.ContainsRune("aeiouAEIOU", inputRunes[charIndex-1]) && readsCharacter(orthographyChoice, char) {
+			return true // marking a long vowel at the end of a word, in orthographies that write length with a colon (pe:, in lexicon)
+		}
+		return inWord && (nextIsLetter || endsSubstitution(inputRunes, charIndex, orthographyChoice))
+	}
+	return false
+}
+
+// returns true if the rules of the orthography read the character, as lexicon and ipa read : for vowel length
+func readsCharacter(orthographyChoice string, char rune) bool {
+	Definition, found := OrthographyDefinitions[orthographyChoice]
+	if !found {
+		return false
+	}
+	for _, RuleStep := range Definition.Normalize {
+		for _, RuleEntry := range RuleStep.Rules {
+			if strings.ContainsRune(RuleEntry.From, char) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// returns whether a word begins with a capital letter, and whether all its letters are capitals (when there are at least two)
+func capitalsOf(inputStr string) (bool, bool) {
+	upperInitial := false
+	letterCount := 0
+	allCaps := true
+	for _, char := range inputStr {
+		if !unicode.IsLetter(char) {
+			continue
+		}
+		if letterCount == 0 {
+			upperInitial = unicode.IsUpper(char)
+		}
+		letterCount++
+		allCaps = allCaps && !unicode.IsLower(char)
+	}
+	return upperInitial, allCaps && letterCount > 1 && upperInitial
+}
+
+// writes a converted word with the capitals the input word had
+// the capital goes on the first letter, after a stress mark if there is one
+func applyCapitals(inputStr string, upperInitial bool, allCaps bool) string {
+	if allCaps {
+		return strings.ToUpper(inputStr)
+	}
+	if !upperInitial || inputStr == "" {
+		return inputStr
+	}
+	stressMark := ""
+	if strings.HasPrefix(inputStr, StressMark) {
+		stressMark, inputStr = StressMark, strings.TrimPrefix(inputStr, StressMark)
+	}
+	inputRunes := []rune(inputStr)
+	if len(inputRunes) == 0 {
+		return stressMark
+	}
+	return stressMark + strings.ToUpper(string(inputRunes[0])) + string(inputRunes[1:])
+}
@@ -0,0 +1,79 @@
+// explains a conversion: the unified orthography every word went through, and every rule that changed it on the way in and out
+// shown in the "explain" panel of the OrthoConverter page, and returned by the api with trace=true
+
+package converter
+
+import (
+	"strings"
+)
+
+type WordTrace struct { // how a single word was converted
+	Input     string             `json:"input"`
+	Unified   string             `json:"unified"`
+	Normalize []TraceStep        `json:"normalize"` // the steps from the input orthography to unified orthography
+	Encode    []OrthographyTrace `json:"encode"`    // the steps from unified orthography to each output orthography
+}
+
+type OrthographyTrace struct { // how a word in unified orthography was turned into one orthography
+	Orthography string      `json:"orthography"`
+	Name        string      `json:"-"` // the display name, for the page
+	Output      string      `json:"output"`
+	Steps       []TraceStep `json:"steps"`
+}
+
+// explains the conversion of every word in a text from orthographyChoice (or "auto") into the target orthographies
+// the text is split the same way as in ConvertAllOptions with the same Options, and escaped sequences
+// (and the words Options.Protect keeps) are left out since no rules apply to them
+func TraceAll(inputStr string, orthographyChoice string, targets []string, Options ConvertOptions) ([]WordTrace, error) {
+	Traces := []WordTrace{}
+	if orthographyChoice == "auto" {
+		orthographyChoice = DetectOrthography(inputStr).Orthography
+	}
+	if _, found := OrthographyNames[orthographyChoice]; !found {
+		return Traces, ErrUnknownOrthography
+	}
+	for _, target := range targets {
+		if _, found := OrthographyNames[target]; !found {
+			return Traces, ErrUnknownOrthography
+		}
+	}
+	if inputStr == "" {
+		return Traces, nil
+	}
+	conversionStringSlice, splitErr := splitConversionStrings(inputStr, orthographyChoice, Options)
+	if splitErr != nil {
+		return Traces, splitErr
+	}
+	for _, stringElement := range conversionStringSlice {
+		if stringElement.Escaped || stringElement.Punctuation {
+			continue
+		}
+		var Trace WordTrace
+		Trace.Input = stringElement.InputString
+		substitutedStr := Substitute(stringElement.InputString, orthographyChoice)
+		Trace.Unified, Trace.Normalize = OrthographyDefinitions[orthographyChoice].TraceNormalize(substitutedStr)
+		if substitutedStr != stringElement.InputString { // the substitution table comes before the rules of the orthography
+			Trace.Normalize = append([]TraceStep{substitutionTrace(stringElement.InputString, substitutedStr, orthographyChoice)}, Trace.Normalize...)
+		}
+		for _, target := range targets {
+			var ThisOrthography OrthographyTrace
+			ThisOrthography.Orthography = target
+			ThisOrthography.Name = OrthographyNames[target]
+			ThisOrthography.Output, ThisOrthography.Steps = OrthographyDefinitions[target].TraceEncode(Trace.Unified)
+			Trace.Encode = append(Trace.Encode, ThisOrthography)
+		}
+		Traces = append(Traces, Trace)
+	}
+	return Traces, nil
+}
+
+// explains the substitutions made in a word, as a step named after the substitution table
+func substitutionTrace(inputStr string, substitutedStr string, orthographyChoice string) TraceStep {
+	Step := TraceStep{Orthography: "substitutions", Before: inputStr, After: substitutedStr}
+	for _, ThisSubstitution := range SubstitutionsFor(orthographyChoice) {
+		if strings.Contains(inputStr, ThisSubstitution.From) {
+			Step.Rules = append(Step.Rules, Rule{From: ThisSubstitution.From, To: ThisSubstitution.To}.String())
+		}
+	}
+	return Step
+}
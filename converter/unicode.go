@@ -0,0 +1,50 @@
+// makes text that looks the same the same text, whatever phone, keyboard or word processor it was typed on
+// a letter and a combining diacritic typed one after the other (e + ◌̀) become the single letter they make (è), and characters
+// that only look like the ones the orthographies use (modifier letter apostrophes, primes, a dotless i with a stroke) become those characters
+// this is done to every word before it is converted, and to every verb before it is conjugated, so equivalent inputs always give the same result
+
+package converter
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// characters that look like the ones the orthographies use, and the characters they are read as
+// they are replaced after diacritics are taken apart (nfd), so that a stroke typed over any i is found, and longer sequences come first
+var confusableReplacer = strings.NewReplacer(
+	// apostrophes
+	"ʼ", "'", // modifier letter apostrophe, which many phone keyboards type
+	"’", "'",
+	"‘", "'",
+	"`", "'",
+	"´", "'",
+	"′", "'", // prime
+	"ʹ", "'", // modifier letter prime
+	"＇", "'", // fullwidth apostrophe
+	// barred i
+	"ı̵", "ɨ", // a dotless or dotted i with a short or long combining stroke
+	"ı̶", "ɨ",
+	"i̵", "ɨ",
+	"i̶", "ɨ",
+	"ᵻ", "ɨ", // latin small capital i with stroke
+	"ı", "i", // a dotless i on its own is an i
+	// cyrillic and greek letters that look like latin ones
+	"а", "a",
+	"е", "e",
+	"і", "i",
+	"о", "o",
+	"ο", "o",
+)
+
+// NormalizeUnicode composes the diacritics of a text (nfc), and replaces characters that only look like the ones the orthographies use,
+// e.g. "è" becomes "è", "ıʼ" becomes "i'" and "ı̵" becomes "ɨ"
+func NormalizeUnicode(inputStr string) string {
+	return norm.NFC.String(confusableReplacer.Replace(norm.NFD.String(inputStr)))
+}
+
+// returns true if the character is an apostrophe, a single quote, or something that looks like one
+func isApostrophe(char rune) bool {
+	return strings.ContainsRune("'’‘`´′ʼʹ＇", char)
+}
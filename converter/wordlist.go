@@ -0,0 +1,168 @@
+// a list of known francis-smith words, for ranking the possible readings of ambiguous words and checking converted words
+// the list is wordlist.txt: one word per line, with # for comments
+// a headword list exported from a dictionary also works: anything after a tab or comma on a line is ignored
+// the server reads the file at WordlistPath, or at the path in the MIKMAW_WORDLIST environment variable
+
+package converter
+
+import (
+	_ "embed"
+	"os"
+	"sort"
+	"strings"
+)
+
+//go:embed wordlist.txt
+var wordlistFile string
+
+// the file that ConverterInit reads the wordlist from, so that words can be added without a rebuild
+var WordlistPath = "converter/wordlist.txt"
+
+type Wordlist map[string]bool // known words, in francis-smith as the converter writes it
+
+type WordCheck struct { // whether a converted word is known, and the nearest known words if it is not
+	Input        string   `json:"input"`
+	FrancisSmith string   `json:"francissmith"`
+	Known        bool     `json:"known"`
+	Suggestions  []string `json:"suggestions,omitempty"`
+}
+
+// the most known words suggested for an unknown word
+const MaxSuggestions = 3
+
+// the characters around a word that are not part of it
+const wordPunctuation = ".,;:?!\"()[]"
+
+// the words the converter knows, built in and then read from WordlistPath by ConverterInit
+var KnownWords = make(Wordlist)
+
+// reads a wordlist from the contents of its file
+// every word is converted from francis-smith to francis-smith, so that e.g. nestɨk and nest*k are the same word
+func ParseWordlist(fileContents string) Wordlist {
+	Words := make(Wordlist)
+	for _, line := range strings.Split(fileContents, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		line, _, _ = strings.Cut(line, "\t")
+		line, _, _ = strings.Cut(line, ",")
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		Words[canonicalWord(line)] = true
+	}
+	return Words
+}
+
+// reads a wordlist file
+func LoadWordlist(path string) (Wordlist, error) {
+	fileContents, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return nil, readErr
+	}
+	return ParseWordlist(string(fileContents)), nil
+}
+
+// returns true if the francis-smith word is in the list
+func (Words Wordlist) Contains(word string) bool {
+	return Words[canonicalWord(strings.ToLower(word))]
+}
+
+// writes a francis-smith word the way the converter does
+func canonicalWord(word string) string {
+	canonicalStr, _ := ConvertWord(word, "francissmith", "francissmith")
+	return canonicalStr
+}
+
+// returns the known words nearest to a francis-smith word, by the number of characters that would have to change
+// words more than a third of their length away (and at least 2) are not suggested
+func (Words Wordlist) Suggest(word string) []string {
+	word = canonicalWord(strings.ToLower(word))
+	maxDistance := len([]rune(word)) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	distances := make(map[string]int)
+	var suggestions []string
+	for knownWord := range Words {
+		distance := editDistance([]rune(word), []rune(knownWord))
+		if distance <= maxDistance {
+			distances[knownWord] = distance
+			suggestions = append(suggestions, knownWord)
+		}
+	}
+	sort.Slice(suggestions, func(firstIndex, secondIndex int) bool { // nearest first, then alphabetically so the order never changes
+		if distances[suggestions[firstIndex]] != distances[suggestions[secondIndex]] {
+			return distances[suggestions[firstIndex]] < distances[suggestions[secondIndex]]
+		}
+		return suggestions[firstIndex] < suggestions[secondIndex]
+	})
+	if len(suggestions) > MaxSuggestions {
+		suggestions = suggestions[:MaxSuggestions]
+	}
+	return suggestions
+}
+
+// the number of characters that have to be added, removed, or changed to turn one word into another (levenshtein distance)
+func editDistance(firstWord []rune, secondWord []rune) int {
+	previousRow := make([]int, len(secondWord)+1)
+	for secondIndex := range previousRow {
+		previousRow[secondIndex] = secondIndex
+	}
+	for firstIndex := 1; firstIndex <= len(firstWord); firstIndex++ {
+		currentRow := make([]int, len(secondWord)+1)
+		currentRow[0] = firstIndex
+		for secondIndex := 1; secondIndex <= len(secondWord); secondIndex++ {
+			substitution := previousRow[secondIndex-1]
+			if firstWord[firstIndex-1] != secondWord[secondIndex-1] {
+				substitution++
+			}
+			currentRow[secondIndex] = min(substitution, previousRow[secondIndex]+1, currentRow[secondIndex-1]+1)
+		}
+		previousRow = currentRow
+	}
+	return previousRow[len(secondWord)]
+}
+
+// checks every word of a text against the wordlist, after converting it to francis-smith
+// for orthographies with ambiguities (pacifique and rand), a word is known if any of its possible readings is, and the known readings are suggested
+// the text is split as in ConvertAllOptions with the same Options, so escaped sequences and the words Options.Protect keeps are not checked
+func CheckAll(inputStr string, orthographyChoice string, Options ConvertOptions) ([]WordCheck, error) {
+	Checks := []WordCheck{}
+	if orthographyChoice == "auto" {
+		orthographyChoice = DetectOrthography(inputStr).Orthography
+	}
+	if _, found := OrthographyNames[orthographyChoice]; !found {
+		return Checks, ErrUnknownOrthography
+	}
+	if inputStr == "" {
+		return Checks, nil
+	}
+	conversionStringSlice, splitErr := splitConversionStrings(inputStr, orthographyChoice, Options)
+	if splitErr != nil {
+		return Checks, splitErr
+	}
+	for _, stringElement := range conversionStringSlice {
+		word := strings.Trim(strings.TrimSpace(stringElement.InputString), wordPunctuation)
+		if stringElement.Escaped || stringElement.Punctuation || word == "" {
+			continue
+		}
+		var Check WordCheck
+		Check.Input = word
+		unifiedString, _ := NormalizeWord(word, orthographyChoice)
+		Check.FrancisSmith = OrthographyDefinitions["francissmith"].EncodeString(unifiedString)
+		Check.Known = KnownWords.Contains(Check.FrancisSmith)
+		if !Check.Known && len(OrthographyDefinitions[orthographyChoice].Ambiguities) > 0 {
+			ReadingCandidates, _ := Candidates(word, orthographyChoice, true)
+			for _, ReadingCandidate := range ReadingCandidates {
+				if ReadingCandidate.Known {
+					Check.Suggestions = append(Check.Suggestions, ReadingCandidate.FrancisSmith)
+				}
+			}
+		}
+		if !Check.Known && len(Check.Suggestions) == 0 {
+			Check.Suggestions = KnownWords.Suggest(Check.FrancisSmith)
+		}
+		Checks = append(Checks, Check)
+	}
+	return Checks, nil
+}
@@ -1,3 +1,5 @@
 module conjugator
 
 go 1.21.0
+
+require golang.org/x/text v0.20.0
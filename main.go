@@ -4,8 +4,8 @@ import (
 	"conjugator/bescherelle"
 	"conjugator/converter"
 	"fmt"
+	"html/template"
 	"net/http"
-	"text/template"
 )
 
 func main() {